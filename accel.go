@@ -0,0 +1,118 @@
+package bno055
+
+import (
+	"context"
+	"fmt"
+)
+
+// AccelRange is the measurement range of the accelerometer.
+type AccelRange byte
+
+const (
+	AccelRange2G  AccelRange = 0x00
+	AccelRange4G  AccelRange = 0x01
+	AccelRange8G  AccelRange = 0x02
+	AccelRange16G AccelRange = 0x03
+)
+
+// AccelBandwidth is the low-pass filter bandwidth of the accelerometer.
+type AccelBandwidth byte
+
+const (
+	AccelBandwidth8Hz    AccelBandwidth = 0x00 // 7.81Hz
+	AccelBandwidth16Hz   AccelBandwidth = 0x01 // 15.63Hz
+	AccelBandwidth31Hz   AccelBandwidth = 0x02 // 31.25Hz
+	AccelBandwidth63Hz   AccelBandwidth = 0x03 // 62.5Hz
+	AccelBandwidth125Hz  AccelBandwidth = 0x04
+	AccelBandwidth250Hz  AccelBandwidth = 0x05
+	AccelBandwidth500Hz  AccelBandwidth = 0x06
+	AccelBandwidth1000Hz AccelBandwidth = 0x07
+)
+
+// AccelPowerMode is the operation mode of the accelerometer itself
+// (see section 3.5.2 of the datasheet).
+type AccelPowerMode byte
+
+const (
+	AccelPowerModeNormal      AccelPowerMode = 0x00
+	AccelPowerModeSuspend     AccelPowerMode = 0x01
+	AccelPowerModeLowPower1   AccelPowerMode = 0x02
+	AccelPowerModeStandby     AccelPowerMode = 0x03
+	AccelPowerModeLowPower2   AccelPowerMode = 0x04
+	AccelPowerModeDeepSuspend AccelPowerMode = 0x05
+)
+
+// AccelConfig maps to the ACC_Config register on page 1. The fusion modes
+// set the accelerometer themselves, so it can only be changed in the
+// non-fusion modes. The reset value is 4G, 62.5Hz and normal power.
+type AccelConfig struct {
+	Range     AccelRange     `json:"range"`
+	Bandwidth AccelBandwidth `json:"bandwidth"`
+	PowerMode AccelPowerMode `json:"power_mode"`
+}
+
+func newAccelConfig(accConfig byte) *AccelConfig {
+	accelConfig := &AccelConfig{
+		Range:     AccelRange(accConfig & AccConfigRange),
+		Bandwidth: AccelBandwidth((accConfig & AccConfigBandwidth) >> 2),
+		PowerMode: AccelPowerMode((accConfig & AccConfigPwrMode) >> 5),
+	}
+
+	return accelConfig
+}
+
+// Returns the ACC_Config value for the config
+func (c *AccelConfig) register() (byte, error) {
+	if c.Range > AccelRange16G {
+		return 0, fmt.Errorf("bno055: invalid accelerometer range 0x%02X", byte(c.Range))
+	}
+
+	if c.Bandwidth > AccelBandwidth1000Hz {
+		return 0, fmt.Errorf("bno055: invalid accelerometer bandwidth 0x%02X", byte(c.Bandwidth))
+	}
+
+	if c.PowerMode > AccelPowerModeDeepSuspend {
+		return 0, fmt.Errorf("bno055: invalid accelerometer power mode 0x%02X", byte(c.PowerMode))
+	}
+
+	return byte(c.PowerMode)<<5 | byte(c.Bandwidth)<<2 | byte(c.Range), nil
+}
+
+func (s *Sensor) AccelConfig() (*AccelConfig, error) {
+	return s.AccelConfigContext(context.Background())
+}
+
+func (s *Sensor) AccelConfigContext(ctx context.Context) (*AccelConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accConfig, err := s.readPage(ctx, Page1, bno055AccConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAccelConfig(accConfig), nil
+}
+
+func (s *Sensor) SetAccelConfig(config *AccelConfig) error {
+	return s.SetAccelConfigContext(context.Background(), config)
+}
+
+// SetAccelConfigContext writes ACC_Config in CONFIG mode. It returns
+// ErrFusionControlled if the current operation mode is a fusion mode.
+func (s *Sensor) SetAccelConfigContext(ctx context.Context, config *AccelConfig) error {
+	accConfig, err := config.register()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.checkNotFusion("accelerometer configuration")
+	if err != nil {
+		return err
+	}
+
+	return s.writeConfig(ctx, Page1, bno055AccConfig, accConfig)
+}
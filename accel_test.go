@@ -0,0 +1,51 @@
+package bno055_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kpeu3i/bno055"
+	"github.com/kpeu3i/bno055/bnotest"
+)
+
+func TestSetAccelConfig(t *testing.T) {
+	sensor, device := newTestSensor(t, bno055.WithOperationMode(bno055.OperationModeAMG))
+
+	config := &bno055.AccelConfig{
+		Range:     bno055.AccelRange16G,
+		Bandwidth: bno055.AccelBandwidth125Hz,
+		PowerMode: bno055.AccelPowerModeLowPower1,
+	}
+
+	err := sensor.SetAccelConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// PWR_MODE in bits 5-7, bandwidth in bits 2-4, range in bits 0-1
+	if val := device.Register(bnotest.Page1, bno055.RegAccConfig); val != 0x53 {
+		t.Fatalf("ACC_Config = 0x%02X, want 0x53", val)
+	}
+
+	accelConfig, err := sensor.AccelConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *accelConfig != *config {
+		t.Fatalf("AccelConfig = %+v, want %+v", *accelConfig, *config)
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeAMG) {
+		t.Fatalf("device left in operation mode 0x%02X, want AMG", mode)
+	}
+}
+
+func TestSetAccelConfigFusion(t *testing.T) {
+	sensor, _ := newTestSensor(t)
+
+	err := sensor.SetAccelConfig(&bno055.AccelConfig{})
+	if !errors.Is(err, bno055.ErrFusionControlled) {
+		t.Fatalf("SetAccelConfig error = %v, want %v", err, bno055.ErrFusionControlled)
+	}
+}
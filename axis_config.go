@@ -1,12 +1,17 @@
 package bno055
 
+import (
+	"fmt"
+	"math"
+)
+
 type AxisConfig struct {
-	X     byte
-	Y     byte
-	Z     byte
-	SignX byte
-	SignY byte
-	SignZ byte
+	X     byte `json:"x"`
+	Y     byte `json:"y"`
+	Z     byte `json:"z"`
+	SignX byte `json:"sign_x"`
+	SignY byte `json:"sign_y"`
+	SignZ byte `json:"sign_z"`
 }
 
 func newAxisConfig(mapConfig, signConfig byte) *AxisConfig {
@@ -41,3 +46,73 @@ func (c *AxisConfig) Signs() byte {
 
 	return signs
 }
+
+// Validate reports whether the config maps every output to a different axis.
+func (c *AxisConfig) Validate() error {
+	if c.X > 2 || c.Y > 2 || c.Z > 2 {
+		return fmt.Errorf("bno055: invalid axis mapping x=%d, y=%d, z=%d", c.X, c.Y, c.Z)
+	}
+
+	if c.X == c.Y || c.X == c.Z || c.Y == c.Z {
+		return fmt.Errorf("bno055: axis mapping x=%d, y=%d, z=%d uses an axis twice", c.X, c.Y, c.Z)
+	}
+
+	if c.SignX > 1 || c.SignY > 1 || c.SignZ > 1 {
+		return fmt.Errorf("bno055: invalid axis signs x=%d, y=%d, z=%d", c.SignX, c.SignY, c.SignZ)
+	}
+
+	return nil
+}
+
+// NewAxisConfigFromMatrix builds the config from a signed permutation or
+// rotation matrix that maps the axes of the chip to the axes of the device:
+// the row of every device axis must hold a single 1 or -1 in the column of
+// the chip axis it follows. Rotation matrices are accepted with small
+// rounding errors, so they can be computed from mounting angles.
+func NewAxisConfigFromMatrix(m [3][3]float64) (*AxisConfig, error) {
+	const tolerance = 1e-3
+
+	var axes, signs [3]byte
+
+	for row := 0; row < 3; row++ {
+		found := false
+
+		for col := 0; col < 3; col++ {
+			val := m[row][col]
+
+			switch {
+			case math.Abs(val) < tolerance:
+				continue
+			case math.Abs(math.Abs(val)-1) >= tolerance || found:
+				return nil, fmt.Errorf("bno055: row %d of the axis matrix is not a signed unit vector", row)
+			}
+
+			found = true
+			axes[row] = byte(col)
+
+			if val < 0 {
+				signs[row] = 1
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("bno055: row %d of the axis matrix is zero", row)
+		}
+	}
+
+	axisConfig := &AxisConfig{
+		X:     axes[0],
+		Y:     axes[1],
+		Z:     axes[2],
+		SignX: signs[0],
+		SignY: signs[1],
+		SignZ: signs[2],
+	}
+
+	err := axisConfig.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return axisConfig, nil
+}
@@ -0,0 +1,397 @@
+// Package bnotest provides an in-memory BNO055 simulator that can be used
+// in place of a real bus when testing code built on top of bno055.Sensor.
+package bnotest
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+const (
+	Page0 = 0
+	Page1 = 1
+
+	chipID = 0xA0
+
+	regPageID = 0x07
+
+	// Page 0
+	regAccelData   = 0x08
+	regMagData     = 0x0E
+	regGyroData    = 0x14
+	regEuler       = 0x1A
+	regQuaternion  = 0x20
+	regLinearAccel = 0x28
+	regGravity     = 0x2E
+	regTemp        = 0x34
+	regCalibStat   = 0x35
+	regSelfTest    = 0x36
+	regIntrStat    = 0x37
+	regSysStat     = 0x39
+	regSysErr      = 0x3A
+	regUnitSel     = 0x3B
+	regOprMode     = 0x3D
+	regSysTrigger  = 0x3F
+	regMagRadius   = 0x6A
+
+	// Page 1
+	regAccConfig = 0x08
+	regIntMsk    = 0x0F
+	regIntEn     = 0x10
+	regGyrAmSet  = 0x1F
+	regUniqueID  = 0x50
+	regUniqueEnd = 0x5F
+
+	sysTriggerSelfTest = 0x01
+	sysTriggerRstSys   = 0x20
+	sysTriggerRstInt   = 0x40
+	sysTriggerClkSel   = 0x80
+
+	operationModeConfig = 0x00
+	operationModeAmg    = 0x07
+)
+
+var ErrClosed = errors.New("bnotest: device is closed")
+
+// Power-on values of the page 0 registers (see section 4.2.1 of the datasheet)
+var page0Defaults = map[byte]byte{
+	0x00: chipID, // CHIP_ID
+	0x01: 0xFB,   // ACC_ID
+	0x02: 0x32,   // MAG_ID
+	0x03: 0x0F,   // GYR_ID
+	0x04: 0x11,   // SW_REV_ID_LSB
+	0x05: 0x03,   // SW_REV_ID_MSB
+	0x06: 0x15,   // BL_REV_ID
+	0x36: 0x0F,   // ST_RESULT
+	0x3B: 0x80,   // UNIT_SEL
+	0x41: 0x24,   // AXIS_MAP_CONFIG
+	0x44: 0x40,   // SIC_MATRIX_0_MSB
+	0x4C: 0x40,   // SIC_MATRIX_4_MSB
+	0x54: 0x40,   // SIC_MATRIX_8_MSB
+	0x67: 0xE8,   // ACC_RADIUS_LSB
+	0x68: 0x03,   // ACC_RADIUS_MSB
+	0x69: 0xE0,   // MAG_RADIUS_LSB
+	0x6A: 0x01,   // MAG_RADIUS_MSB
+}
+
+// Power-on values of the page 1 registers (see section 4.2.2 of the datasheet)
+var page1Defaults = map[byte]byte{
+	0x08: 0x0D, // ACC_Config
+	0x09: 0x6D, // MAG_Config
+	0x0A: 0x38, // GYR_Config_0
+	0x11: 0x14, // ACC_AM_THRES
+	0x12: 0x03, // ACC_INT_Settings
+	0x13: 0x0F, // ACC_HG_DURATION
+	0x14: 0xC0, // ACC_HG_THRES
+	0x15: 0x0A, // ACC_NM_THRES
+	0x16: 0x0B, // ACC_NM_SET
+	0x18: 0x01, // GYR_HR_X_SET
+	0x19: 0x19, // GYR_DUR_X
+	0x1A: 0x01, // GYR_HR_Y_SET
+	0x1B: 0x19, // GYR_DUR_Y
+	0x1C: 0x01, // GYR_HR_Z_SET
+	0x1D: 0x19, // GYR_DUR_Z
+	0x1E: 0x04, // GYR_AM_THRES
+	0x1F: 0x0A, // GYR_AM_SET
+}
+
+// Device simulates the BNO055 register map. It implements bno055.I2CBus.
+//
+// The simulator follows the access rules of the datasheet: read-only
+// registers ignore writes, write-only registers read as zero, and
+// configuration registers only accept writes in CONFIG mode. Data
+// registers can be scripted with the Set* methods.
+type Device struct {
+	mu     sync.Mutex
+	pages  [2][256]byte
+	page   int
+	resets int
+	closed bool
+}
+
+func NewDevice() *Device {
+	device := &Device{}
+	device.reset()
+
+	return device
+}
+
+func (d *Device) Read(reg byte) (byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return 0, ErrClosed
+	}
+
+	return d.read(reg), nil
+}
+
+func (d *Device) Write(reg byte, val byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrClosed
+	}
+
+	d.write(reg, val)
+
+	return nil
+}
+
+func (d *Device) ReadBuffer(reg byte, buff []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrClosed
+	}
+
+	for i := range buff {
+		buff[i] = d.read(reg + byte(i))
+	}
+
+	return nil
+}
+
+func (d *Device) WriteBuffer(reg byte, buff []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrClosed
+	}
+
+	for i, val := range buff {
+		d.write(reg+byte(i), val)
+	}
+
+	return nil
+}
+
+func (d *Device) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.closed = true
+
+	return nil
+}
+
+// Register returns the raw value of a register, bypassing the access rules.
+func (d *Device) Register(page int, reg byte) byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.pages[page][reg]
+}
+
+// SetRegister sets the raw value of a register, bypassing the access rules.
+func (d *Device) SetRegister(page int, reg byte, val byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pages[page][reg] = val
+}
+
+// SetRegisters sets consecutive raw register values, bypassing the access rules.
+func (d *Device) SetRegisters(page int, reg byte, vals []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, val := range vals {
+		d.pages[page][reg+byte(i)] = val
+	}
+}
+
+// Page returns the currently selected register page.
+func (d *Device) Page() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.page
+}
+
+// OperationMode returns the current value of the OPR_MODE register.
+func (d *Device) OperationMode() byte {
+	return d.Register(Page0, regOprMode)
+}
+
+// Resets returns how many times the device was reset via SYS_TRIGGER.
+func (d *Device) Resets() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.resets
+}
+
+func (d *Device) SetAccelerometer(x, y, z int16) {
+	d.setVector(regAccelData, x, y, z)
+}
+
+func (d *Device) SetMagnetometer(x, y, z int16) {
+	d.setVector(regMagData, x, y, z)
+}
+
+func (d *Device) SetGyroscope(x, y, z int16) {
+	d.setVector(regGyroData, x, y, z)
+}
+
+func (d *Device) SetEuler(heading, roll, pitch int16) {
+	d.setVector(regEuler, heading, roll, pitch)
+}
+
+func (d *Device) SetLinearAccelerometer(x, y, z int16) {
+	d.setVector(regLinearAccel, x, y, z)
+}
+
+func (d *Device) SetGravity(x, y, z int16) {
+	d.setVector(regGravity, x, y, z)
+}
+
+func (d *Device) SetQuaternion(w, x, y, z int16) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint16(buf[0:], uint16(w))
+	binary.LittleEndian.PutUint16(buf[2:], uint16(x))
+	binary.LittleEndian.PutUint16(buf[4:], uint16(y))
+	binary.LittleEndian.PutUint16(buf[6:], uint16(z))
+
+	d.SetRegisters(Page0, regQuaternion, buf)
+}
+
+func (d *Device) SetTemperature(t int8) {
+	d.SetRegister(Page0, regTemp, byte(t))
+}
+
+// SetCalibrationStatus sets CALIB_STAT from the system, gyroscope,
+// accelerometer and magnetometer calibration levels (0..3).
+func (d *Device) SetCalibrationStatus(system, gyroscope, accelerometer, magnetometer byte) {
+	status := (system&0x03)<<6 | (gyroscope&0x03)<<4 | (accelerometer&0x03)<<2 | magnetometer&0x03
+
+	d.SetRegister(Page0, regCalibStat, status)
+}
+
+// SetInterruptStatus sets INT_STA. It is cleared by a RST_INT trigger.
+func (d *Device) SetInterruptStatus(status byte) {
+	d.SetRegister(Page0, regIntrStat, status)
+}
+
+func (d *Device) setVector(reg byte, x, y, z int16) {
+	buf := make([]byte, 6)
+	binary.LittleEndian.PutUint16(buf[0:], uint16(x))
+	binary.LittleEndian.PutUint16(buf[2:], uint16(y))
+	binary.LittleEndian.PutUint16(buf[4:], uint16(z))
+
+	d.SetRegisters(Page0, reg, buf)
+}
+
+func (d *Device) reset() {
+	d.pages = [2][256]byte{}
+	d.page = Page0
+
+	for reg, val := range page0Defaults {
+		d.pages[Page0][reg] = val
+	}
+
+	for reg, val := range page1Defaults {
+		d.pages[Page1][reg] = val
+	}
+
+	for reg := byte(regUniqueID); reg <= regUniqueEnd; reg++ {
+		d.pages[Page1][reg] = reg
+	}
+}
+
+func (d *Device) read(reg byte) byte {
+	if reg == regPageID {
+		return byte(d.page)
+	}
+
+	if d.page == Page0 && reg == regSysTrigger {
+		// SYS_TRIGGER is write-only
+		return 0
+	}
+
+	return d.pages[d.page][reg]
+}
+
+func (d *Device) write(reg byte, val byte) {
+	if reg == regPageID {
+		d.page = int(val & 0x01)
+		return
+	}
+
+	if d.page == Page1 {
+		d.writePage1(reg, val)
+		return
+	}
+
+	d.writePage0(reg, val)
+}
+
+func (d *Device) writePage0(reg byte, val byte) {
+	switch {
+	case reg == regOprMode:
+		d.setOperationMode(val & 0x0F)
+	case reg == regSysTrigger:
+		d.trigger(val)
+	case reg < regUnitSel || reg > regMagRadius || reg == regUnitSel+1:
+		// Identification, data and status registers are read-only
+	case d.configMode():
+		d.pages[Page0][reg] = val
+	}
+}
+
+func (d *Device) writePage1(reg byte, val byte) {
+	switch {
+	case reg == regIntMsk || reg == regIntEn:
+		// Interrupt settings can be modified in any operation mode
+		d.pages[Page1][reg] = val
+	case reg < regAccConfig || reg > regGyrAmSet:
+		// Unique ID and reserved registers are read-only
+	case d.configMode():
+		d.pages[Page1][reg] = val
+	}
+}
+
+func (d *Device) setOperationMode(mode byte) {
+	d.pages[Page0][regOprMode] = mode
+
+	switch {
+	case mode == operationModeConfig:
+		d.pages[Page0][regSysStat] = 0x00
+	case mode <= operationModeAmg:
+		// System running without fusion algorithms
+		d.pages[Page0][regSysStat] = 0x06
+	default:
+		// Sensor fusion algorithm running
+		d.pages[Page0][regSysStat] = 0x05
+	}
+}
+
+func (d *Device) trigger(val byte) {
+	if val&sysTriggerRstSys != 0 {
+		d.resets++
+		d.reset()
+		return
+	}
+
+	if val&sysTriggerRstInt != 0 {
+		d.pages[Page0][regIntrStat] = 0
+	}
+
+	if val&sysTriggerSelfTest != 0 && d.configMode() {
+		d.pages[Page0][regSelfTest] = 0x0F
+		d.pages[Page0][regSysErr] = 0x00
+	}
+
+	if d.configMode() {
+		d.pages[Page0][regSysTrigger] = val & sysTriggerClkSel
+	}
+}
+
+func (d *Device) configMode() bool {
+	return d.pages[Page0][regOprMode] == operationModeConfig
+}
@@ -0,0 +1,125 @@
+package bnotest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrNoDevice = errors.New("bnotest: no device selected")
+
+// Mux simulates a TCA9548A multiplexer with a device on each channel. It
+// implements io.ByteWriter for the control register and bno055.I2CBus for
+// the devices behind it, so both sides of a tca9548a.Mux can be tested.
+type Mux struct {
+	mu         sync.Mutex
+	devices    [8]*Device
+	mask       byte
+	selections int
+}
+
+func NewMux() *Mux {
+	return &Mux{}
+}
+
+// Attach connects device to channel n (0..7).
+func (m *Mux) Attach(n int, device *Device) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.devices[n] = device
+}
+
+// WriteByte writes the control register.
+func (m *Mux) WriteByte(mask byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mask = mask
+	m.selections++
+
+	return nil
+}
+
+// Mask returns the current value of the control register.
+func (m *Mux) Mask() byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.mask
+}
+
+// Selections returns how many times the control register was written.
+func (m *Mux) Selections() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.selections
+}
+
+func (m *Mux) Read(reg byte) (byte, error) {
+	device, err := m.device()
+	if err != nil {
+		return 0, err
+	}
+
+	return device.Read(reg)
+}
+
+func (m *Mux) Write(reg byte, val byte) error {
+	device, err := m.device()
+	if err != nil {
+		return err
+	}
+
+	return device.Write(reg, val)
+}
+
+func (m *Mux) ReadBuffer(reg byte, buff []byte) error {
+	device, err := m.device()
+	if err != nil {
+		return err
+	}
+
+	return device.ReadBuffer(reg, buff)
+}
+
+func (m *Mux) WriteBuffer(reg byte, buff []byte) error {
+	device, err := m.device()
+	if err != nil {
+		return err
+	}
+
+	return device.WriteBuffer(reg, buff)
+}
+
+func (m *Mux) Close() error {
+	return nil
+}
+
+// Returns the device on the only enabled channel. Enabling several channels
+// with a device on each would make them answer at the same time.
+func (m *Mux) device() (*Device, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var selected *Device
+
+	for n, device := range m.devices {
+		if m.mask&(1<<uint(n)) == 0 || device == nil {
+			continue
+		}
+
+		if selected != nil {
+			return nil, fmt.Errorf("bnotest: bus collision, control register is 0x%02X", m.mask)
+		}
+
+		selected = device
+	}
+
+	if selected == nil {
+		return nil, ErrNoDevice
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,37 @@
+package bnotest
+
+import (
+	"context"
+)
+
+// Pin is an in-memory edge source for bno055.Sensor.SetEdgeSource.
+// An edge triggered while nobody waits is delivered to the next wait;
+// further edges are merged into it, like a latched interrupt line.
+type Pin struct {
+	edges chan struct{}
+}
+
+func NewPin() *Pin {
+	pin := &Pin{
+		edges: make(chan struct{}, 1),
+	}
+
+	return pin
+}
+
+// Trigger reports an edge.
+func (p *Pin) Trigger() {
+	select {
+	case p.edges <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Pin) WaitForEdge(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.edges:
+		return nil
+	}
+}
@@ -0,0 +1,282 @@
+package bno055
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// TemperatureSource selects the sensor the temperature is measured by.
+type TemperatureSource byte
+
+const (
+	TemperatureSourceAccelerometer TemperatureSource = 0x00
+	TemperatureSourceGyroscope     TemperatureSource = 0x01
+)
+
+var ErrVerifyFailed = errors.New("bno055: register read back differs from written value")
+
+// Config describes the configuration of the sensor. Nil fields are left
+// unchanged by Apply.
+type Config struct {
+	OperationMode      *OperationMode
+	PowerMode          *PowerMode
+	Units              *Units
+	AxisConfig         *AxisConfig
+	ExternalCrystal    *bool
+	TemperatureSource  *TemperatureSource
+	CalibrationOffsets CalibrationOffsets
+	SoftIronMatrix     *SICMatrix
+
+	// Sensor settings on page 1, only allowed if the operation mode is not
+	// a fusion mode
+	Accel *AccelConfig
+	Gyro  *GyroConfig
+	Mag   *MagConfig
+}
+
+// A block of consecutive registers set by a Config
+type configWrite struct {
+	name string
+	page Page
+	reg  byte
+	val  []byte
+}
+
+// Returns the registers the config sets, in the order they are written
+func (c *Config) writes() ([]configWrite, error) {
+	var writes []configWrite
+
+	if c.Units != nil {
+		unitSel, err := c.Units.register()
+		if err != nil {
+			return nil, err
+		}
+
+		writes = append(writes, configWrite{"units", Page0, bno055UnitSel, []byte{unitSel}})
+	}
+
+	if c.PowerMode != nil {
+		if !c.PowerMode.IsValid() {
+			return nil, fmt.Errorf("bno055: invalid power mode 0x%02X", byte(*c.PowerMode))
+		}
+
+		writes = append(writes, configWrite{"power mode", Page0, bno055PwrMode, []byte{byte(*c.PowerMode)}})
+	}
+
+	if c.TemperatureSource != nil {
+		if *c.TemperatureSource > TemperatureSourceGyroscope {
+			return nil, fmt.Errorf("bno055: invalid temperature source 0x%02X", byte(*c.TemperatureSource))
+		}
+
+		writes = append(writes, configWrite{"temperature source", Page0, bno055TempSource, []byte{byte(*c.TemperatureSource)}})
+	}
+
+	if c.AxisConfig != nil {
+		err := c.AxisConfig.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		writes = append(writes, configWrite{"axis config", Page0, bno055AxisMapConfig, []byte{c.AxisConfig.Mappings(), c.AxisConfig.Signs()}})
+	}
+
+	if c.SoftIronMatrix != nil {
+		writes = append(writes, configWrite{"soft iron matrix", Page0, bno055SicMatrix0Lsb, c.SoftIronMatrix.bytes()})
+	}
+
+	if c.CalibrationOffsets != nil {
+		if len(c.CalibrationOffsets) != 22 {
+			return nil, fmt.Errorf("bno055: calibration offsets must be 22 bytes, got %d", len(c.CalibrationOffsets))
+		}
+
+		writes = append(writes, configWrite{"calibration offsets", Page0, bno055AccelOffsetXLsb, []byte(c.CalibrationOffsets)})
+	}
+
+	if c.Accel != nil {
+		accConfig, err := c.Accel.register()
+		if err != nil {
+			return nil, err
+		}
+
+		writes = append(writes, configWrite{"accelerometer", Page1, bno055AccConfig, []byte{accConfig}})
+	}
+
+	if c.Mag != nil {
+		magConfig, err := c.Mag.register()
+		if err != nil {
+			return nil, err
+		}
+
+		writes = append(writes, configWrite{"magnetometer", Page1, bno055MagConfig, []byte{magConfig}})
+	}
+
+	if c.Gyro != nil {
+		gyrConfig, err := c.Gyro.registers()
+		if err != nil {
+			return nil, err
+		}
+
+		writes = append(writes, configWrite{"gyroscope", Page1, bno055GyroConfig0, gyrConfig})
+	}
+
+	return writes, nil
+}
+
+func (s *Sensor) Apply(config *Config) error {
+	return s.ApplyContext(context.Background(), config)
+}
+
+// ApplyContext writes the registers of the config that differ from the
+// sensor in a single CONFIG mode window, reads them back and then switches
+// to the operation mode of the config (or back to the current one).
+// If a step fails, the registers already written are restored.
+func (s *Sensor) ApplyContext(ctx context.Context, config *Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.apply(ctx, config)
+
+	return err
+}
+
+// Applies the config and returns the names of the changed settings,
+// must be called with the lock held
+func (s *Sensor) apply(ctx context.Context, config *Config) ([]string, error) {
+	writes, err := config.writes()
+	if err != nil {
+		return nil, err
+	}
+
+	prevMode := s.opMode
+
+	mode := OperationMode(s.opMode)
+	if config.OperationMode != nil {
+		mode = *config.OperationMode
+	}
+
+	if !mode.IsValid() {
+		return nil, fmt.Errorf("bno055: invalid operation mode 0x%02X", byte(mode))
+	}
+
+	if mode.IsFusion() && (config.Accel != nil || config.Gyro != nil || config.Mag != nil) {
+		return nil, fmt.Errorf("%w: sensor configuration cannot be changed in %s mode", ErrFusionControlled, mode)
+	}
+
+	clkSel := s.clkSel
+	if config.ExternalCrystal != nil {
+		clkSel = 0x00
+		if *config.ExternalCrystal {
+			clkSel = SysTriggerClkSel
+		}
+	}
+
+	err = s.setOperationMode(ctx, bno055OperationModeConfig)
+	if err != nil {
+		return nil, s.restoreMode(prevMode, err)
+	}
+
+	var (
+		changed   []configWrite
+		originals [][]byte
+		names     []string
+	)
+
+	// Restores the written registers and the operation mode
+	rollback := func(err error) ([]string, error) {
+		ctx := context.Background()
+
+		var rollbackErr error
+		for i := len(changed) - 1; i >= 0; i-- {
+			writeErr := s.writeBufferPage(ctx, changed[i].page, changed[i].reg, originals[i])
+			if writeErr != nil && rollbackErr == nil {
+				rollbackErr = writeErr
+			}
+		}
+
+		if clkSel != s.clkSel {
+			writeErr := s.write(ctx, bno055SysTrigger, s.clkSel)
+			if writeErr != nil && rollbackErr == nil {
+				rollbackErr = writeErr
+			}
+		}
+
+		modeErr := s.setOperationMode(ctx, prevMode)
+		if modeErr != nil && rollbackErr == nil {
+			rollbackErr = modeErr
+		}
+
+		if rollbackErr != nil {
+			return nil, fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+
+		return nil, err
+	}
+
+	for _, write := range writes {
+		current := make([]byte, len(write.val))
+		err = s.readBufferPage(ctx, write.page, write.reg, current)
+		if err != nil {
+			return rollback(err)
+		}
+
+		if bytes.Equal(current, write.val) {
+			continue
+		}
+
+		// Recorded before the write, so a partial write is restored as well
+		changed = append(changed, write)
+		originals = append(originals, current)
+		names = append(names, write.name)
+
+		err = s.writeBufferPage(ctx, write.page, write.reg, write.val)
+		if err != nil {
+			return rollback(err)
+		}
+	}
+
+	if clkSel != s.clkSel {
+		// SYS_TRIGGER is write-only, so the clock selection cannot be verified
+		err = s.write(ctx, bno055SysTrigger, clkSel)
+		if err != nil {
+			return rollback(err)
+		}
+
+		names = append(names, "external crystal")
+	}
+
+	for _, write := range changed {
+		val := make([]byte, len(write.val))
+		err = s.readBufferPage(ctx, write.page, write.reg, val)
+		if err != nil {
+			return rollback(err)
+		}
+
+		if !bytes.Equal(val, write.val) {
+			return rollback(fmt.Errorf("%w: %s", ErrVerifyFailed, write.name))
+		}
+	}
+
+	err = s.setOperationMode(ctx, byte(mode))
+	if err != nil {
+		return rollback(err)
+	}
+
+	if byte(mode) != prevMode {
+		names = append(names, "operation mode")
+	}
+
+	for _, write := range changed {
+		switch {
+		case write.page == Page0 && write.reg == bno055UnitSel:
+			s.unitSel = write.val[0]
+		case write.page == Page0 && write.reg == bno055PwrMode:
+			s.pwrMode = write.val[0]
+		}
+	}
+
+	s.clkSel = clkSel
+
+	return names, nil
+}
@@ -0,0 +1,164 @@
+package bno055_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kpeu3i/bno055"
+	"github.com/kpeu3i/bno055/bnotest"
+)
+
+func TestApply(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	mode := bno055.OperationModeAMG
+	units := bno055.Units{Acceleration: bno055.UnitMilliG}
+
+	config := &bno055.Config{
+		OperationMode:      &mode,
+		Units:              &units,
+		CalibrationOffsets: testOffsets,
+		Accel:              &bno055.AccelConfig{Range: bno055.AccelRange8G},
+	}
+
+	err := sensor.Apply(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeAMG) {
+		t.Fatalf("device in operation mode 0x%02X, want AMG", mode)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegUnitSel); val != bno055.UnitSelAccMg {
+		t.Fatalf("UNIT_SEL = 0x%02X, want 0x%02X", val, bno055.UnitSelAccMg)
+	}
+
+	if val := device.Register(bnotest.Page1, bno055.RegAccConfig); val != 0x02 {
+		t.Fatalf("ACC_Config = 0x%02X, want 0x02", val)
+	}
+
+	if units := sensor.Units(); units.Acceleration != bno055.UnitMilliG {
+		t.Fatalf("acceleration unit = %s, want %s", units.Acceleration, bno055.UnitMilliG)
+	}
+}
+
+func TestApplyRollback(t *testing.T) {
+	errBus := errors.New("bus error")
+
+	device := bnotest.NewDevice()
+	bus := &hookBus{Device: device}
+
+	sensor, err := bno055.NewSensorFromBus(bus, bno055.WithoutReset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fail the calibration offsets, which are written after the units
+	bus.hook = func(page int, reg byte, buff []byte) error {
+		if page == bnotest.Page0 && reg == bno055.RegAccOffsetX && len(buff) > 1 {
+			return errBus
+		}
+
+		return nil
+	}
+
+	mode := bno055.OperationModeAMG
+	units := bno055.Units{Acceleration: bno055.UnitMilliG}
+
+	err = sensor.Apply(&bno055.Config{
+		OperationMode:      &mode,
+		Units:              &units,
+		CalibrationOffsets: testOffsets,
+	})
+	if !errors.Is(err, errBus) {
+		t.Fatalf("Apply error = %v, want %v", err, errBus)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegUnitSel); val != 0x00 {
+		t.Fatalf("UNIT_SEL = 0x%02X after rollback, want 0x00", val)
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeNDOF) {
+		t.Fatalf("device in operation mode 0x%02X after rollback, want NDOF", mode)
+	}
+
+	if mode := sensor.OperationMode(); mode != bno055.OperationModeNDOF {
+		t.Fatalf("OperationMode = %s after rollback, want NDOF", mode)
+	}
+
+	if units := sensor.Units(); units.Acceleration != bno055.UnitMetersPerSecondSquared {
+		t.Fatalf("acceleration unit = %s after rollback, want %s", units.Acceleration, bno055.UnitMetersPerSecondSquared)
+	}
+}
+
+func TestApplyCanceledEnteringConfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	device := bnotest.NewDevice()
+	bus := &hookBus{Device: device}
+
+	sensor, err := bno055.NewSensorFromBus(bus, bno055.WithoutReset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancel during the delay of the switch to CONFIG mode
+	bus.hook = func(page int, reg byte, buff []byte) error {
+		if page == bnotest.Page0 && reg == bno055.RegOprMode && buff[0] == byte(bno055.OperationModeConfig) {
+			cancel()
+		}
+
+		return nil
+	}
+
+	units := bno055.Units{Acceleration: bno055.UnitMilliG}
+
+	err = sensor.ApplyContext(ctx, &bno055.Config{Units: &units})
+
+	var canceledErr *bno055.CanceledError
+	if !errors.As(err, &canceledErr) {
+		t.Fatalf("ApplyContext error = %v, want a CanceledError", err)
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeNDOF) {
+		t.Fatalf("device left in operation mode 0x%02X, want NDOF", mode)
+	}
+
+	if mode := sensor.OperationMode(); mode != bno055.OperationModeNDOF {
+		t.Fatalf("OperationMode = %s, want NDOF", mode)
+	}
+}
+
+func TestApplyFusionControlled(t *testing.T) {
+	sensor, _ := newTestSensor(t)
+
+	err := sensor.Apply(&bno055.Config{Gyro: &bno055.GyroConfig{}})
+	if !errors.Is(err, bno055.ErrFusionControlled) {
+		t.Fatalf("Apply error = %v, want %v", err, bno055.ErrFusionControlled)
+	}
+}
+
+func TestStatusKeepsExternalCrystal(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	err := sensor.UseExternalCrystal(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := sensor.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.SelfTest != 0x0F {
+		t.Fatalf("self test result = 0x%02X, want 0x0F", status.SelfTest)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegSysTrigger); val&bno055.SysTriggerClkSel == 0 {
+		t.Fatal("self test cleared the external crystal selection")
+	}
+}
@@ -151,6 +151,32 @@ const (
 	bno055MagRadiusLsb   = 0x69
 	bno055MagRadiusMsb   = 0x6A
 
+	// PAGE1 register definition start
+	bno055AccConfig       = 0x08
+	bno055MagConfig       = 0x09
+	bno055GyroConfig0     = 0x0A
+	bno055GyroConfig1     = 0x0B
+	bno055AccSleepConfig  = 0x0C
+	bno055GyroSleepConfig = 0x0D
+	bno055IntMsk          = 0x0F
+	bno055IntEn           = 0x10
+	bno055AccAmThres      = 0x11
+	bno055AccIntSettings  = 0x12
+	bno055AccHgDuration   = 0x13
+	bno055AccHgThres      = 0x14
+	bno055AccNmThres      = 0x15
+	bno055AccNmSet        = 0x16
+	bno055GyroIntSetting  = 0x17
+	bno055GyroHrXSet      = 0x18
+	bno055GyroDurX        = 0x19
+	bno055GyroHrYSet      = 0x1A
+	bno055GyroDurY        = 0x1B
+	bno055GyroHrZSet      = 0x1C
+	bno055GyroDurZ        = 0x1D
+	bno055GyroAmThres     = 0x1E
+	bno055GyroAmSet       = 0x1F
+	bno055UniqueID        = 0x50
+
 	bno055PowerModeNormal   = 0x00
 	bno055PowerModeLowpower = 0x01
 	bno055PowerModeSuspend  = 0x02
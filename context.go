@@ -0,0 +1,190 @@
+package bno055
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// I2CBusContext is implemented by buses whose operations can be cancelled.
+// Sensor uses it when available, so a deadline also stops bus retries.
+type I2CBusContext interface {
+	I2CBus
+	ReadContext(ctx context.Context, reg byte) (byte, error)
+	WriteContext(ctx context.Context, reg byte, val byte) error
+	ReadBufferContext(ctx context.Context, reg byte, buff []byte) error
+	WriteBufferContext(ctx context.Context, reg byte, buff []byte) error
+}
+
+// CanceledError is returned when a context stops a sensor operation.
+// Step tells where the operation stopped. If it stopped in CONFIG mode, the
+// previous operation mode is restored with a background context, but the
+// registers already written are not rolled back.
+type CanceledError struct {
+	Step string
+	Err  error
+}
+
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("bno055: stopped at %s: %v", e.Step, e.Err)
+}
+
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+func (s *Sensor) read(ctx context.Context, reg byte) (byte, error) {
+	return s.readPage(ctx, Page0, reg)
+}
+
+func (s *Sensor) write(ctx context.Context, reg byte, val byte) error {
+	return s.writePage(ctx, Page0, reg, val)
+}
+
+func (s *Sensor) readBuffer(ctx context.Context, reg byte, buff []byte) error {
+	return s.readBufferPage(ctx, Page0, reg, buff)
+}
+
+func (s *Sensor) writeBuffer(ctx context.Context, reg byte, buff []byte) error {
+	return s.writeBufferPage(ctx, Page0, reg, buff)
+}
+
+func (s *Sensor) readPage(ctx context.Context, page Page, reg byte) (byte, error) {
+	err := s.selectPage(ctx, page)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.readBus(ctx, "read of register "+registerName(page, reg), reg)
+}
+
+func (s *Sensor) writePage(ctx context.Context, page Page, reg byte, val byte) error {
+	err := s.selectPage(ctx, page)
+	if err != nil {
+		return err
+	}
+
+	return s.writeBus(ctx, "write of register "+registerName(page, reg), reg, val)
+}
+
+func (s *Sensor) readBufferPage(ctx context.Context, page Page, reg byte, buff []byte) error {
+	step := "read of registers " + registerRange(page, reg, len(buff))
+
+	err := s.selectPage(ctx, page)
+	if err != nil {
+		return err
+	}
+
+	err = canceled(ctx, step)
+	if err != nil {
+		return err
+	}
+
+	if bus, ok := s.bus.(I2CBusContext); ok {
+		err = bus.ReadBufferContext(ctx, reg, buff)
+	} else {
+		err = s.bus.ReadBuffer(reg, buff)
+	}
+
+	return wrapCanceled(ctx, step, err)
+}
+
+func (s *Sensor) writeBufferPage(ctx context.Context, page Page, reg byte, buff []byte) error {
+	step := "write of registers " + registerRange(page, reg, len(buff))
+
+	err := s.selectPage(ctx, page)
+	if err != nil {
+		return err
+	}
+
+	err = canceled(ctx, step)
+	if err != nil {
+		return err
+	}
+
+	if bus, ok := s.bus.(I2CBusContext); ok {
+		err = bus.WriteBufferContext(ctx, reg, buff)
+	} else {
+		err = s.bus.WriteBuffer(reg, buff)
+	}
+
+	return wrapCanceled(ctx, step, err)
+}
+
+// Writes PAGE_ID if the page is not the active one (or it is unknown)
+func (s *Sensor) selectPage(ctx context.Context, page Page) error {
+	if s.page == int(page) {
+		return nil
+	}
+
+	err := s.writeBus(ctx, fmt.Sprintf("switch to page %d", page), bno055PageID, byte(page))
+	if err != nil {
+		s.page = pageUnknown
+		return err
+	}
+
+	s.page = int(page)
+
+	return nil
+}
+
+func (s *Sensor) readBus(ctx context.Context, step string, reg byte) (byte, error) {
+	err := canceled(ctx, step)
+	if err != nil {
+		return 0, err
+	}
+
+	var val byte
+	if bus, ok := s.bus.(I2CBusContext); ok {
+		val, err = bus.ReadContext(ctx, reg)
+	} else {
+		val, err = s.bus.Read(reg)
+	}
+
+	return val, wrapCanceled(ctx, step, err)
+}
+
+func (s *Sensor) writeBus(ctx context.Context, step string, reg byte, val byte) error {
+	err := canceled(ctx, step)
+	if err != nil {
+		return err
+	}
+
+	if bus, ok := s.bus.(I2CBusContext); ok {
+		err = bus.WriteContext(ctx, reg, val)
+	} else {
+		err = s.bus.Write(reg, val)
+	}
+
+	return wrapCanceled(ctx, step, err)
+}
+
+func (s *Sensor) sleep(ctx context.Context, d time.Duration, step string) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return &CanceledError{Step: step, Err: ctx.Err()}
+	case <-timer.C:
+		return nil
+	}
+}
+
+func canceled(ctx context.Context, step string) error {
+	err := ctx.Err()
+	if err != nil {
+		return &CanceledError{Step: step, Err: err}
+	}
+
+	return nil
+}
+
+// Bus errors caused by the context are reported with the step they stopped at
+func wrapCanceled(ctx context.Context, step string, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return &CanceledError{Step: step, Err: ctx.Err()}
+	}
+
+	return err
+}
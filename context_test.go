@@ -0,0 +1,47 @@
+package bno055_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kpeu3i/bno055"
+	"github.com/kpeu3i/bno055/bnotest"
+)
+
+func TestConfigWindowRestoresModeOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	device := bnotest.NewDevice()
+	bus := &hookBus{Device: device}
+
+	sensor, err := bno055.NewSensorFromBus(bus, bno055.WithoutReset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancel once the sensor is in CONFIG mode
+	bus.hook = func(page int, reg byte, buff []byte) error {
+		if page == bnotest.Page0 && reg == bno055.RegOprMode && buff[0] == byte(bno055.OperationModeConfig) {
+			cancel()
+		}
+
+		return nil
+	}
+
+	err = sensor.CalibrateContext(ctx, testOffsets)
+
+	var canceledErr *bno055.CanceledError
+	if !errors.As(err, &canceledErr) {
+		t.Fatalf("CalibrateContext error = %v, want a CanceledError", err)
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeNDOF) {
+		t.Fatalf("device left in operation mode 0x%02X, want NDOF", mode)
+	}
+
+	if mode := sensor.OperationMode(); mode != bno055.OperationModeNDOF {
+		t.Fatalf("OperationMode = %s, want NDOF", mode)
+	}
+}
@@ -0,0 +1,143 @@
+package bno055
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kpeu3i/bno055/i2c"
+)
+
+const (
+	// Address with the COM3 pin low
+	DefaultAddress = 0x28
+	// Address with the COM3 pin high
+	AlternativeAddress = 0x29
+)
+
+// Descriptor identifies a sensor found by Discover. Bus and Address can be
+// passed to NewSensor.
+type Descriptor struct {
+	Bus      int
+	Address  uint8
+	Revision *Revision
+}
+
+func Discover(options ...Option) ([]*Descriptor, error) {
+	return DiscoverContext(context.Background(), options...)
+}
+
+// DiscoverContext probes both sensor addresses on every /dev/i2c-* adapter
+// and returns the devices that answer with the BNO055 chip ID. The sensors
+// are only read, not initialized. Adapters that cannot be opened are
+// skipped; an error is returned only if none of them could be opened.
+func DiscoverContext(ctx context.Context, options ...Option) ([]*Descriptor, error) {
+	config := &config{}
+	for _, option := range options {
+		option(config)
+	}
+
+	buses, err := adapters()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		descriptors []*Descriptor
+		openErr     error
+		opened      int
+	)
+
+	for _, bus := range buses {
+		manager, err := i2c.NewManager(bus, config.retryCount, config.retryTimeout, config.busOptions()...)
+		if err != nil {
+			openErr = err
+			continue
+		}
+
+		opened++
+
+		for _, addr := range []uint8{DefaultAddress, AlternativeAddress} {
+			revision, err := probe(ctx, manager, addr)
+			if err != nil {
+				if ctx.Err() != nil {
+					manager.Close()
+					return nil, err
+				}
+
+				continue
+			}
+
+			if revision == nil {
+				continue
+			}
+
+			descriptor := &Descriptor{
+				Bus:      bus,
+				Address:  addr,
+				Revision: revision,
+			}
+
+			descriptors = append(descriptors, descriptor)
+		}
+
+		manager.Close()
+	}
+
+	if opened == 0 && openErr != nil {
+		return nil, openErr
+	}
+
+	return descriptors, nil
+}
+
+// Returns the revision of the sensor at addr, or nil if another device answers
+func probe(ctx context.Context, manager *i2c.Manager, addr uint8) (*Revision, error) {
+	bus, err := manager.Bus(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer bus.Close()
+
+	// Assume page 0, so nothing is written to a device that may not be a BNO055
+	sensor := &Sensor{
+		bus:  bus,
+		page: int(Page0),
+	}
+
+	id, err := sensor.read(ctx, bno055ChipID)
+	if err != nil {
+		return nil, err
+	}
+
+	if id != bno055Id {
+		return nil, nil
+	}
+
+	return sensor.RevisionContext(ctx)
+}
+
+// Returns the numbers of the /dev/i2c-* adapters in ascending order
+func adapters() ([]int, error) {
+	paths, err := filepath.Glob("/dev/i2c-*")
+	if err != nil {
+		return nil, err
+	}
+
+	var buses []int
+
+	for _, path := range paths {
+		bus, err := strconv.Atoi(strings.TrimPrefix(path, "/dev/i2c-"))
+		if err != nil {
+			continue
+		}
+
+		buses = append(buses, bus)
+	}
+
+	sort.Ints(buses)
+
+	return buses, nil
+}
@@ -62,7 +62,7 @@ func main() {
 		panic(err)
 	}
 
-	fmt.Printf("*** Temperature: t=%v\n", temperature)
+	fmt.Printf("*** Temperature: t=%v%s\n", temperature.Value, temperature.Unit)
 
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
@@ -90,6 +90,6 @@ func main() {
 	// *** Status: system=133, system_error=0, self_test=15
 	// *** Revision: software=785, bootloader=21, accelerometer=251, gyroscope=15, magnetometer=50
 	// *** Axis: x=0, y=1, z=2, sign_x=0, sign_y=0, sign_z=0
-	// *** Temperature: t=27
+	// *** Temperature: t=27°C
 	// *** Euler angles: x=2.312, y=2.000, z=91.688
 }
@@ -1 +1,3 @@
 module github.com/kpeu3i/bno055
+
+go 1.13
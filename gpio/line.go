@@ -0,0 +1,150 @@
+// Package gpio waits for edges on a GPIO line through the Linux GPIO
+// character device (/dev/gpiochipN). A Line can be passed to
+// bno055.Sensor.SetEdgeSource to wait for the INT pin of the sensor.
+package gpio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	// _IOWR(0xB4, 0x04, struct gpioevent_request)
+	gpioGetLineEventIoctl = 0xC030B404
+
+	gpioHandleRequestInput = 0x01
+
+	consumerLabel = "bno055"
+)
+
+// Edge selects which transitions of the line are reported.
+type Edge uint32
+
+const (
+	EdgeRising  Edge = 0x01
+	EdgeFalling Edge = 0x02
+	EdgeBoth    Edge = EdgeRising | EdgeFalling
+)
+
+var ErrClosed = errors.New("gpio: line is closed")
+
+// struct gpioevent_request from linux/gpio.h
+type gpioEventRequest struct {
+	lineOffset    uint32
+	handleFlags   uint32
+	eventFlags    uint32
+	consumerLabel [32]byte
+	fd            int32
+}
+
+// Line is a GPIO line requested for edge events.
+type Line struct {
+	mu     sync.Mutex
+	rc     *os.File
+	closed bool
+}
+
+// NewLine requests edge events for the line at offset of the chip
+// (for example "/dev/gpiochip0").
+func NewLine(chip string, offset int, edge Edge) (*Line, error) {
+	file, err := os.OpenFile(chip, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	req := gpioEventRequest{
+		lineOffset:  uint32(offset),
+		handleFlags: gpioHandleRequestInput,
+		eventFlags:  uint32(edge),
+	}
+
+	copy(req.consumerLabel[:], consumerLabel)
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), gpioGetLineEventIoctl, uintptr(unsafe.Pointer(&req)))
+	if errno != 0 {
+		return nil, fmt.Errorf("gpio: request of line %d on %s: %w", offset, chip, errno)
+	}
+
+	// A non-blocking descriptor is handled by the runtime poller, so reads
+	// can be interrupted through deadlines
+	err = syscall.SetNonblock(int(req.fd), true)
+	if err != nil {
+		syscall.Close(int(req.fd))
+		return nil, err
+	}
+
+	line := &Line{
+		rc: os.NewFile(uintptr(req.fd), fmt.Sprintf("%s:%d", chip, offset)),
+	}
+
+	return line, nil
+}
+
+// WaitForEdge blocks until the next edge of the line, until ctx is done or
+// until the line is closed. It must not be called concurrently.
+func (l *Line) WaitForEdge(ctx context.Context) error {
+	l.mu.Lock()
+	closed := l.closed
+	l.mu.Unlock()
+
+	if closed {
+		return ErrClosed
+	}
+
+	err := l.rc.SetReadDeadline(time.Time{})
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		select {
+		case <-ctx.Done():
+			l.rc.SetReadDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+
+	// struct gpioevent_data: 64-bit timestamp, 32-bit event id and padding
+	event := make([]byte, 16)
+	_, err = io.ReadFull(l.rc, event)
+
+	close(stop)
+	<-done
+
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case errors.Is(err, os.ErrClosed):
+		return ErrClosed
+	}
+
+	return err
+}
+
+func (l *Line) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+
+	l.closed = true
+
+	return l.rc.Close()
+}
@@ -0,0 +1,143 @@
+package bno055
+
+import (
+	"context"
+	"fmt"
+)
+
+// GyroRange is the measurement range of the gyroscope.
+type GyroRange byte
+
+const (
+	GyroRange2000DPS GyroRange = 0x00
+	GyroRange1000DPS GyroRange = 0x01
+	GyroRange500DPS  GyroRange = 0x02
+	GyroRange250DPS  GyroRange = 0x03
+	GyroRange125DPS  GyroRange = 0x04
+)
+
+// GyroBandwidth is the low-pass filter bandwidth of the gyroscope.
+type GyroBandwidth byte
+
+const (
+	GyroBandwidth523Hz GyroBandwidth = 0x00
+	GyroBandwidth230Hz GyroBandwidth = 0x01
+	GyroBandwidth116Hz GyroBandwidth = 0x02
+	GyroBandwidth47Hz  GyroBandwidth = 0x03
+	GyroBandwidth23Hz  GyroBandwidth = 0x04
+	GyroBandwidth12Hz  GyroBandwidth = 0x05
+	GyroBandwidth64Hz  GyroBandwidth = 0x06
+	GyroBandwidth32Hz  GyroBandwidth = 0x07
+)
+
+// GyroPowerMode is the operation mode of the gyroscope itself
+// (see section 3.5.3 of the datasheet).
+type GyroPowerMode byte
+
+const (
+	GyroPowerModeNormal            GyroPowerMode = 0x00
+	GyroPowerModeFastPowerUp       GyroPowerMode = 0x01
+	GyroPowerModeDeepSuspend       GyroPowerMode = 0x02
+	GyroPowerModeSuspend           GyroPowerMode = 0x03
+	GyroPowerModeAdvancedPowerSave GyroPowerMode = 0x04
+)
+
+// GyroConfig maps to the GYR_Config_0 and GYR_Config_1 registers on page 1.
+// The fusion modes set the gyroscope themselves, so it can only be changed
+// in the non-fusion modes. The reset value is 2000dps, 32Hz and normal power.
+//
+// The range does not change the scale of Gyroscope, which follows the
+// selected units.
+type GyroConfig struct {
+	Range     GyroRange     `json:"range"`
+	Bandwidth GyroBandwidth `json:"bandwidth"`
+	PowerMode GyroPowerMode `json:"power_mode"`
+}
+
+func newGyroConfig(gyrConfig0 byte, gyrConfig1 byte) *GyroConfig {
+	gyroConfig := &GyroConfig{
+		Range:     GyroRange(gyrConfig0 & GyrConfig0Range),
+		Bandwidth: GyroBandwidth((gyrConfig0 & GyrConfig0Bandwidth) >> 3),
+		PowerMode: GyroPowerMode(gyrConfig1 & GyrConfig1PwrMode),
+	}
+
+	return gyroConfig
+}
+
+// Returns the GYR_Config_0 and GYR_Config_1 values for the config
+func (c *GyroConfig) registers() ([]byte, error) {
+	if c.Range > GyroRange125DPS {
+		return nil, fmt.Errorf("bno055: invalid gyroscope range 0x%02X", byte(c.Range))
+	}
+
+	if c.Bandwidth > GyroBandwidth32Hz {
+		return nil, fmt.Errorf("bno055: invalid gyroscope bandwidth 0x%02X", byte(c.Bandwidth))
+	}
+
+	if c.PowerMode > GyroPowerModeAdvancedPowerSave {
+		return nil, fmt.Errorf("bno055: invalid gyroscope power mode 0x%02X", byte(c.PowerMode))
+	}
+
+	return []byte{byte(c.Bandwidth)<<3 | byte(c.Range), byte(c.PowerMode)}, nil
+}
+
+func (s *Sensor) GyroConfig() (*GyroConfig, error) {
+	return s.GyroConfigContext(context.Background())
+}
+
+func (s *Sensor) GyroConfigContext(ctx context.Context) (*GyroConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readGyroConfig(ctx)
+}
+
+func (s *Sensor) SetGyroConfig(config *GyroConfig) error {
+	return s.SetGyroConfigContext(context.Background(), config)
+}
+
+// SetGyroConfigContext writes GYR_Config_0 and GYR_Config_1 in CONFIG mode
+// and reads them back. It returns ErrFusionControlled if the current
+// operation mode is a fusion mode.
+func (s *Sensor) SetGyroConfigContext(ctx context.Context, config *GyroConfig) error {
+	gyrConfig, err := config.registers()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.checkNotFusion("gyroscope configuration")
+	if err != nil {
+		return err
+	}
+
+	err = s.inConfigMode(ctx, func() error {
+		return s.writeBufferPage(ctx, Page1, bno055GyroConfig0, gyrConfig)
+	})
+	if err != nil {
+		return err
+	}
+
+	gyroConfig, err := s.readGyroConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *gyroConfig != *config {
+		return fmt.Errorf("bno055: gyroscope configuration read back as %+v", *gyroConfig)
+	}
+
+	return nil
+}
+
+func (s *Sensor) readGyroConfig(ctx context.Context) (*GyroConfig, error) {
+	buf := make([]byte, 2)
+	err := s.readBufferPage(ctx, Page1, bno055GyroConfig0, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGyroConfig(buf[0], buf[1]), nil
+}
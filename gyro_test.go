@@ -0,0 +1,32 @@
+package bno055_test
+
+import (
+	"testing"
+
+	"github.com/kpeu3i/bno055"
+	"github.com/kpeu3i/bno055/bnotest"
+)
+
+func TestSetGyroConfig(t *testing.T) {
+	sensor, device := newTestSensor(t, bno055.WithOperationMode(bno055.OperationModeAMG))
+
+	config := &bno055.GyroConfig{
+		Range:     bno055.GyroRange250DPS,
+		Bandwidth: bno055.GyroBandwidth47Hz,
+		PowerMode: bno055.GyroPowerModeAdvancedPowerSave,
+	}
+
+	err := sensor.SetGyroConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Bandwidth in bits 3-5, range in bits 0-2
+	if val := device.Register(bnotest.Page1, bno055.RegGyrConfig0); val != 0x1B {
+		t.Fatalf("GYR_Config_0 = 0x%02X, want 0x1B", val)
+	}
+
+	if val := device.Register(bnotest.Page1, bno055.RegGyrConfig1); val != 0x04 {
+		t.Fatalf("GYR_Config_1 = 0x%02X, want 0x04", val)
+	}
+}
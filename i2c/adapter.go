@@ -0,0 +1,140 @@
+package i2c
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kpeu3i/bno055/retry"
+)
+
+var ErrManagerClosed = errors.New("i2c: manager is closed")
+
+// An adapter is an open /dev/i2c-N device shared by one or more buses.
+// All transactions hold its lock, and the target address is switched
+// with I2C_SLAVE only when it differs from the last one used.
+type adapter struct {
+	mu    sync.Mutex
+	rc    *os.File
+	addr  int
+	refs  int
+	funcs uint64
+}
+
+func openAdapter(bus int) (*adapter, error) {
+	file, err := os.OpenFile(fmt.Sprintf("/dev/i2c-%d", bus), os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &adapter{
+		rc:    file,
+		addr:  -1,
+		funcs: functionality(file.Fd()),
+	}
+
+	return a, nil
+}
+
+// Must be called with the lock held
+func (a *adapter) bind(addr uint8) error {
+	if a.addr == int(addr) {
+		return nil
+	}
+
+	err := ioctlInt(a.rc.Fd(), i2cSlave, uintptr(addr))
+	if err != nil {
+		a.addr = -1
+		return err
+	}
+
+	a.addr = int(addr)
+
+	return nil
+}
+
+// Must be called with the lock held
+func (a *adapter) retain() {
+	a.refs++
+}
+
+// Must be called with the lock held, closes the device once unused
+func (a *adapter) release() error {
+	a.refs--
+	if a.refs > 0 {
+		return nil
+	}
+
+	return a.rc.Close()
+}
+
+// Manager owns a single I2C adapter and hands out a Bus for each device
+// address on it. Buses from the same manager can be used from concurrent
+// goroutines: each transaction holds the adapter lock and switches the
+// target address first if needed.
+type Manager struct {
+	adapter *adapter
+	config  *config
+	closed  bool
+}
+
+func NewManager(bus int, retryCount int, retryTimeout time.Duration, options ...Option) (*Manager, error) {
+	config := &config{}
+	for _, option := range options {
+		option(config)
+	}
+
+	if config.retryPolicy == nil {
+		config.retryPolicy = retry.Fixed(retryCount, retryTimeout)
+	}
+
+	adapter, err := openAdapter(bus)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter.retain()
+
+	manager := &Manager{
+		adapter: adapter,
+		config:  config,
+	}
+
+	return manager, nil
+}
+
+// Bus returns a handle for the device at addr. Closing it releases the
+// handle only; the adapter is closed once the manager and all of its
+// buses are closed.
+func (m *Manager) Bus(addr uint8) (*Bus, error) {
+	m.adapter.mu.Lock()
+	defer m.adapter.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+
+	err := m.adapter.bind(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	m.adapter.retain()
+
+	return newBus(m.adapter, addr, m.config), nil
+}
+
+func (m *Manager) Close() error {
+	m.adapter.mu.Lock()
+	defer m.adapter.mu.Unlock()
+
+	if m.closed {
+		return ErrManagerClosed
+	}
+
+	m.closed = true
+
+	return m.adapter.release()
+}
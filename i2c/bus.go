@@ -1,146 +1,419 @@
 package i2c
 
 import (
-	"fmt"
+	"context"
 	"os"
-	"sync"
+	"runtime"
 	"syscall"
 	"time"
+	"unsafe"
+
+	"github.com/kpeu3i/bno055/retry"
 )
 
 const (
 	i2cSlave = 0x0703
+	i2cFuncs = 0x0705
+	i2cRdwr  = 0x0707
+
+	i2cFuncI2C = 0x00000001
+
+	i2cMsgRead = 0x0001
 )
 
+// Mirrors struct i2c_msg from <linux/i2c.h>
+type i2cMsg struct {
+	addr  uint16
+	flags uint16
+	len   uint16
+	buf   unsafe.Pointer
+}
+
+// Mirrors struct i2c_rdwr_ioctl_data from <linux/i2c-dev.h>
+type i2cRdwrData struct {
+	msgs  unsafe.Pointer
+	nmsgs uint32
+}
+
+type Option func(config *config)
+
+type config struct {
+	splitTransactions bool
+	smbus             bool
+	retryPolicy       retry.Policy
+	chunkSize         int
+	chunkDelay        time.Duration
+}
+
 type Bus struct {
-	retryCount   int
-	retryTimeout time.Duration
-	mu           sync.Mutex
-	rc           *os.File
+	addr       uint8
+	policy     retry.Policy
+	combined   bool
+	smbus      bool
+	smbusBlock bool
+	chunkSize  int
+	chunkDelay time.Duration
+	adapter    *adapter
+	closed     bool
+}
+
+// WithSplitTransactions disables combined write-then-read transactions, so
+// a register read is done as a separate write and read. By default it is
+// only used for adapters that support neither I2C_FUNC_I2C nor SMBus
+// byte-data transfers.
+func WithSplitTransactions() Option {
+	return func(config *config) {
+		config.splitTransactions = true
+	}
+}
+
+// WithSMBus makes the bus use I2C_SMBUS transfers even if the adapter
+// supports plain I2C. By default they are only used for adapters that
+// support SMBus byte-data transfers but lack I2C_FUNC_I2C (e.g. i2c-stub).
+func WithSMBus() Option {
+	return func(config *config) {
+		config.smbus = true
+	}
+}
+
+// WithRetryPolicy overrides the fixed retry count and timeout passed to NewBus.
+func WithRetryPolicy(policy retry.Policy) Option {
+	return func(config *config) {
+		config.retryPolicy = policy
+	}
 }
 
-func NewBus(addr uint8, bus int, retryCount int, retryTimeout time.Duration) (*Bus, error) {
-	file, err := os.OpenFile(fmt.Sprintf("/dev/i2c-%d", bus), os.O_RDWR, 0600)
+// WithChunking splits ReadBuffer and WriteBuffer transfers into chunks of
+// at most size bytes, each addressed to its own start register and retried
+// on its own, with delay between chunks. This helps adapters that cannot
+// handle the BNO055 clock stretching during long transfers (e.g. Raspberry Pi).
+func WithChunking(size int, delay time.Duration) Option {
+	return func(config *config) {
+		config.chunkSize = size
+		config.chunkDelay = delay
+	}
+}
+
+func NewBus(addr uint8, bus int, retryCount int, retryTimeout time.Duration, options ...Option) (*Bus, error) {
+	config := &config{}
+	for _, option := range options {
+		option(config)
+	}
+
+	if config.retryPolicy == nil {
+		config.retryPolicy = retry.Fixed(retryCount, retryTimeout)
+	}
+
+	adapter, err := openAdapter(bus)
 	if err != nil {
 		return nil, err
 	}
 
-	err = ioctl(file.Fd(), i2cSlave, uintptr(addr))
+	err = adapter.bind(addr)
 	if err != nil {
+		adapter.rc.Close()
 		return nil, err
 	}
 
+	adapter.retain()
+
+	return newBus(adapter, addr, config), nil
+}
+
+func newBus(adapter *adapter, addr uint8, config *config) *Bus {
 	i2cBus := &Bus{
-		retryCount:   retryCount,
-		retryTimeout: retryTimeout,
-		rc:           file,
+		addr:       addr,
+		policy:     config.retryPolicy,
+		chunkSize:  config.chunkSize,
+		chunkDelay: config.chunkDelay,
+		adapter:    adapter,
 	}
 
-	return i2cBus, nil
+	plain := adapter.funcs&i2cFuncI2C != 0
+	smbus := adapter.funcs&i2cFuncSMBusByteData == i2cFuncSMBusByteData
+
+	switch {
+	case smbus && (config.smbus || !plain):
+		i2cBus.smbus = true
+		i2cBus.smbusBlock = adapter.funcs&i2cFuncSMBusI2CBlock == i2cFuncSMBusI2CBlock
+
+		// Block transfers are limited to 32 bytes
+		if i2cBus.chunkSize <= 0 || i2cBus.chunkSize > i2cSMBusBlockMax {
+			i2cBus.chunkSize = i2cSMBusBlockMax
+		}
+	case plain && !config.splitTransactions:
+		i2cBus.combined = true
+	}
+
+	return i2cBus
 }
 
 func (b *Bus) Read(reg byte) (byte, error) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	buf := make([]byte, 1)
+	return b.ReadContext(context.Background(), reg)
+}
 
-	err := retry(func() error {
-		_, err := b.rc.Write([]byte{reg})
-		if err != nil {
-			return err
-		}
+func (b *Bus) ReadContext(ctx context.Context, reg byte) (byte, error) {
+	b.adapter.mu.Lock()
+	defer b.adapter.mu.Unlock()
 
-		_, err = b.rc.Read(buf)
+	err := b.bind()
+	if err != nil {
+		return 0, err
+	}
 
-		if err != nil {
-			return err
-		}
+	buf := make([]byte, 1)
 
-		return nil
-	}, b.retryCount, b.retryTimeout)
+	err = b.policy.Do(ctx, func() error {
+		return b.readRegisters(reg, buf)
+	})
 
 	return buf[0], err
 }
 
 func (b *Bus) Write(reg byte, val byte) error {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+	return b.WriteContext(context.Background(), reg, val)
+}
 
-	buf := []byte{reg, val}
+func (b *Bus) WriteContext(ctx context.Context, reg byte, val byte) error {
+	b.adapter.mu.Lock()
+	defer b.adapter.mu.Unlock()
 
-	err := retry(func() error {
-		_, err := b.rc.Write(buf)
-		if err != nil {
-			return err
-		}
+	err := b.bind()
+	if err != nil {
+		return err
+	}
 
-		return nil
-	}, b.retryCount, b.retryTimeout)
+	buf := []byte{val}
+
+	err = b.policy.Do(ctx, func() error {
+		return b.writeRegisters(reg, buf)
+	})
 
 	return err
 }
 
 func (b *Bus) ReadBuffer(reg byte, buff []byte) error {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+	return b.ReadBufferContext(context.Background(), reg, buff)
+}
 
-	err := retry(func() error {
-		_, err := b.rc.Write([]byte{reg})
-		if err != nil {
-			return err
-		}
+func (b *Bus) ReadBufferContext(ctx context.Context, reg byte, buff []byte) error {
+	b.adapter.mu.Lock()
+	defer b.adapter.mu.Unlock()
 
-		_, err = b.rc.Read(buff)
-		if err != nil {
-			return err
-		}
+	err := b.bind()
+	if err != nil {
+		return err
+	}
 
-		return nil
-	}, b.retryCount, b.retryTimeout)
+	err = b.chunked(ctx, reg, buff, func(reg byte, chunk []byte) error {
+		return b.policy.Do(ctx, func() error {
+			return b.readRegisters(reg, chunk)
+		})
+	})
 
 	return err
 }
 
 func (b *Bus) WriteBuffer(reg byte, buff []byte) error {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+	return b.WriteBufferContext(context.Background(), reg, buff)
+}
+
+func (b *Bus) WriteBufferContext(ctx context.Context, reg byte, buff []byte) error {
+	b.adapter.mu.Lock()
+	defer b.adapter.mu.Unlock()
+
+	err := b.bind()
+	if err != nil {
+		return err
+	}
+
+	err = b.chunked(ctx, reg, buff, func(reg byte, chunk []byte) error {
+		return b.policy.Do(ctx, func() error {
+			return b.writeRegisters(reg, chunk)
+		})
+	})
 
-	err := retry(func() error {
-		_, err := b.rc.Write(append([]byte{reg}, buff...))
+	return err
+}
+
+// WriteByte writes a single byte to the device without a register address,
+// as needed for devices with a single control register such as I2C multiplexers.
+func (b *Bus) WriteByte(val byte) error {
+	b.adapter.mu.Lock()
+	defer b.adapter.mu.Unlock()
+
+	err := b.bind()
+	if err != nil {
+		return err
+	}
+
+	err = b.policy.Do(context.Background(), func() error {
+		if b.smbus {
+			return b.smbusWriteByte(val)
+		}
+
+		_, err := b.adapter.rc.Write([]byte{val})
 		if err != nil {
 			return err
 		}
 
 		return nil
-	}, b.retryCount, b.retryTimeout)
+	})
 
 	return err
 }
 
 func (b *Bus) Close() error {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+	b.adapter.mu.Lock()
+	defer b.adapter.mu.Unlock()
 
-	return b.rc.Close()
+	if b.closed {
+		return os.ErrClosed
+	}
+
+	b.closed = true
+
+	return b.adapter.release()
 }
 
-func retry(fn func() error, retryCount int, timeout time.Duration) error {
-	var err error
+// Must be called with the adapter lock held
+func (b *Bus) bind() error {
+	if b.closed {
+		return os.ErrClosed
+	}
+
+	return b.adapter.bind(b.addr)
+}
+
+// Calls fn for each chunk of buff along with the register it starts at
+func (b *Bus) chunked(ctx context.Context, reg byte, buff []byte, fn func(reg byte, chunk []byte) error) error {
+	if b.chunkSize <= 0 || len(buff) <= b.chunkSize {
+		return fn(reg, buff)
+	}
+
+	for offset := 0; offset < len(buff); offset += b.chunkSize {
+		if offset > 0 && b.chunkDelay > 0 {
+			timer := time.NewTimer(b.chunkDelay)
 
-	for i := 1; i <= retryCount+1; i++ {
-		err = fn()
-		if err == nil {
-			break
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
 		}
 
-		time.Sleep(timeout)
+		end := offset + b.chunkSize
+		if end > len(buff) {
+			end = len(buff)
+		}
+
+		err := fn(reg+byte(offset), buff[offset:end])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Bus) readRegisters(reg byte, buff []byte) error {
+	if b.smbus {
+		return b.smbusRead(reg, buff)
+	}
+
+	if b.combined {
+		return b.transfer(reg, buff)
 	}
 
+	_, err := b.adapter.rc.Write([]byte{reg})
+	if err != nil {
+		return err
+	}
+
+	_, err = b.adapter.rc.Read(buff)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (b *Bus) writeRegisters(reg byte, buff []byte) error {
+	if b.smbus {
+		return b.smbusWrite(reg, buff)
+	}
+
+	_, err := b.adapter.rc.Write(append([]byte{reg}, buff...))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Writes the register address and reads the data back in a single
+// repeated-start transaction, so no other master can interleave
+func (b *Bus) transfer(reg byte, buff []byte) error {
+	if len(buff) == 0 {
+		return nil
+	}
+
+	regBuf := []byte{reg}
+
+	msgs := []i2cMsg{
+		{
+			addr: uint16(b.addr),
+			len:  1,
+			buf:  unsafe.Pointer(&regBuf[0]),
+		},
+		{
+			addr:  uint16(b.addr),
+			flags: i2cMsgRead,
+			len:   uint16(len(buff)),
+			buf:   unsafe.Pointer(&buff[0]),
+		},
+	}
+
+	data := i2cRdwrData{
+		msgs:  unsafe.Pointer(&msgs[0]),
+		nmsgs: uint32(len(msgs)),
+	}
+
+	err := ioctl(b.adapter.rc.Fd(), i2cRdwr, unsafe.Pointer(&data))
+
+	runtime.KeepAlive(regBuf)
+	runtime.KeepAlive(buff)
+	runtime.KeepAlive(msgs)
+	runtime.KeepAlive(&data)
+
 	return err
 }
 
-func ioctl(fd, cmd, arg uintptr) error {
+// Returns the I2C_FUNCS bits of the adapter, or none if they are unknown
+func functionality(fd uintptr) uint64 {
+	var funcs uint64
+
+	err := ioctl(fd, i2cFuncs, unsafe.Pointer(&funcs))
+	if err != nil {
+		return 0
+	}
+
+	return funcs
+}
+
+// The pointer is converted in the call expression, so the memory it refers
+// to stays valid until the syscall returns
+func ioctl(fd, cmd uintptr, arg unsafe.Pointer) error {
+	_, _, err := syscall.Syscall6(syscall.SYS_IOCTL, fd, cmd, uintptr(arg), 0, 0, 0)
+	if err != 0 {
+		return err
+	}
+
+	return nil
+}
+
+func ioctlInt(fd, cmd, arg uintptr) error {
 	_, _, err := syscall.Syscall6(syscall.SYS_IOCTL, fd, cmd, arg, 0, 0, 0)
 	if err != 0 {
 		return err
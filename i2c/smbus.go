@@ -0,0 +1,111 @@
+package i2c
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+const (
+	i2cSMBus = 0x0720
+
+	i2cSMBusWrite = 0
+	i2cSMBusRead  = 1
+
+	i2cSMBusByte         = 1
+	i2cSMBusByteData     = 2
+	i2cSMBusI2CBlockData = 8
+
+	// Maximum number of data bytes in a block transfer
+	i2cSMBusBlockMax = 32
+
+	i2cFuncSMBusReadByteData  = 0x00080000
+	i2cFuncSMBusWriteByteData = 0x00100000
+	i2cFuncSMBusReadI2CBlock  = 0x04000000
+	i2cFuncSMBusWriteI2CBlock = 0x08000000
+
+	i2cFuncSMBusByteData = i2cFuncSMBusReadByteData | i2cFuncSMBusWriteByteData
+	i2cFuncSMBusI2CBlock = i2cFuncSMBusReadI2CBlock | i2cFuncSMBusWriteI2CBlock
+)
+
+// Mirrors struct i2c_smbus_ioctl_data from <linux/i2c-dev.h>
+type i2cSMBusData struct {
+	readWrite uint8
+	command   uint8
+	size      uint32
+	data      unsafe.Pointer
+}
+
+// The register address is sent as the SMBus command. Block transfers use
+// the I2C block variant, which has no byte count on the wire, and fall
+// back to one byte-data transfer per register if the adapter lacks it.
+func (b *Bus) smbusRead(reg byte, buff []byte) error {
+	if !b.smbusBlock {
+		for i := range buff {
+			var data [i2cSMBusBlockMax + 2]byte
+
+			err := b.smbusTransfer(i2cSMBusRead, reg+byte(i), i2cSMBusByteData, &data)
+			if err != nil {
+				return err
+			}
+
+			buff[i] = data[0]
+		}
+
+		return nil
+	}
+
+	var data [i2cSMBusBlockMax + 2]byte
+	data[0] = byte(len(buff))
+
+	err := b.smbusTransfer(i2cSMBusRead, reg, i2cSMBusI2CBlockData, &data)
+	if err != nil {
+		return err
+	}
+
+	copy(buff, data[1:1+len(buff)])
+
+	return nil
+}
+
+func (b *Bus) smbusWrite(reg byte, buff []byte) error {
+	if !b.smbusBlock || len(buff) == 1 {
+		for i, val := range buff {
+			var data [i2cSMBusBlockMax + 2]byte
+			data[0] = val
+
+			err := b.smbusTransfer(i2cSMBusWrite, reg+byte(i), i2cSMBusByteData, &data)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var data [i2cSMBusBlockMax + 2]byte
+	data[0] = byte(len(buff))
+	copy(data[1:], buff)
+
+	return b.smbusTransfer(i2cSMBusWrite, reg, i2cSMBusI2CBlockData, &data)
+}
+
+// SMBus "send byte": val goes out as the command with no data
+func (b *Bus) smbusWriteByte(val byte) error {
+	return b.smbusTransfer(i2cSMBusWrite, val, i2cSMBusByte, nil)
+}
+
+func (b *Bus) smbusTransfer(readWrite uint8, command byte, size uint32, data *[i2cSMBusBlockMax + 2]byte) error {
+	args := i2cSMBusData{
+		readWrite: readWrite,
+		command:   command,
+		size:      size,
+		data:      unsafe.Pointer(data),
+	}
+
+	err := ioctl(b.adapter.rc.Fd(), i2cSMBus, unsafe.Pointer(&args))
+
+	runtime.KeepAlive(data)
+	runtime.KeepAlive(&args)
+
+	return err
+}
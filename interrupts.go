@@ -0,0 +1,371 @@
+package bno055
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Interrupts is a set of interrupt sources, as found in INT_STA, INT_MSK and
+// INT_EN (see section 3.8 of the datasheet).
+type Interrupts byte
+
+const (
+	InterruptGyroAnyMotion  Interrupts = IntGyrAM
+	InterruptGyroHighRate   Interrupts = IntGyrHighRate
+	InterruptAccelHighG     Interrupts = IntAccHighG
+	InterruptAccelAnyMotion Interrupts = IntAccAM
+	// Raised by no-motion or slow-motion, depending on AccelNoMotion.SlowMotion
+	InterruptAccelNoMotion Interrupts = IntAccNM
+)
+
+// Axes is a set of axes an interrupt is evaluated on.
+type Axes byte
+
+const (
+	AxisX   Axes = 0x01
+	AxisY   Axes = 0x02
+	AxisZ   Axes = 0x04
+	AxesAll Axes = AxisX | AxisY | AxisZ
+)
+
+var ErrNoEdgeSource = errors.New("bno055: no edge source is set")
+
+// EdgeSource waits for edges on the GPIO line wired to the INT pin.
+// See the gpio package for a Linux implementation.
+type EdgeSource interface {
+	WaitForEdge(ctx context.Context) error
+}
+
+// AccelAnyMotion fires when the slope of the acceleration exceeds the
+// threshold (see section 3.8.2.1).
+type AccelAnyMotion struct {
+	// 1 LSB is 3.91mg at 2G, 7.81mg at 4G, 15.63mg at 8G and 31.25mg at 16G
+	Threshold uint8
+	// Consecutive samples above the threshold minus one, 0-3
+	Duration uint8
+}
+
+// AccelNoMotion fires when the slope stays below the threshold for the
+// duration, or with SlowMotion when it stays above it (see section 3.8.2.2).
+type AccelNoMotion struct {
+	// Same scale as AccelAnyMotion.Threshold
+	Threshold uint8
+	// 0-63, see section 4.4.16 for the mapping to seconds
+	Duration   uint8
+	SlowMotion bool
+}
+
+// AccelHighG fires when the acceleration exceeds the threshold
+// (see section 3.8.2.3).
+type AccelHighG struct {
+	Axes Axes
+	// 1 LSB is 7.81mg at 2G, 15.63mg at 4G, 31.25mg at 8G and 62.5mg at 16G
+	Threshold uint8
+	// (1 + Duration) * 2ms
+	Duration uint8
+}
+
+// GyroAnyMotion fires when the angular rate slope exceeds the threshold
+// (see section 3.8.2.4).
+type GyroAnyMotion struct {
+	Axes Axes
+	// 0-127, 1 LSB is 1dps at 2000dps and scales with the gyroscope range
+	Threshold uint8
+	// (1 + Samples) * 4 samples, 0-3
+	Samples uint8
+	// 8, 16, 32 or 64 samples, 0-3
+	AwakeDuration uint8
+	Unfiltered    bool
+}
+
+// GyroHighRate fires when the angular rate exceeds the threshold of an axis
+// (see section 3.8.2.5).
+type GyroHighRate struct {
+	Axes       Axes
+	X          GyroHighRateAxis
+	Y          GyroHighRateAxis
+	Z          GyroHighRateAxis
+	Unfiltered bool
+}
+
+type GyroHighRateAxis struct {
+	// 0-31, 1 LSB is 62.5dps at 2000dps and scales with the gyroscope range
+	Threshold uint8
+	// 0-3, same scale as Threshold
+	Hysteresis uint8
+	// (1 + Duration) * 2.5ms
+	Duration uint8
+}
+
+// InterruptConfig maps to the interrupt registers on page 1, from INT_MSK
+// (0x0F) to GYR_AM_SET (0x1F). The reset value enables nothing.
+type InterruptConfig struct {
+	// Interrupts that set INT_STA (INT_EN)
+	Enabled Interrupts
+	// Interrupts that drive the INT pin (INT_MSK)
+	Pin Interrupts
+
+	// Axes shared by any-motion and no-motion
+	AccelMotionAxes Axes
+	AccelAnyMotion  AccelAnyMotion
+	AccelNoMotion   AccelNoMotion
+	AccelHighG      AccelHighG
+	GyroAnyMotion   GyroAnyMotion
+	GyroHighRate    GyroHighRate
+}
+
+func (i Interrupts) Has(interrupts Interrupts) bool {
+	return i&interrupts == interrupts
+}
+
+func (i Interrupts) String() string {
+	names := []struct {
+		interrupt Interrupts
+		name      string
+	}{
+		{InterruptGyroAnyMotion, "gyroscope any-motion"},
+		{InterruptGyroHighRate, "gyroscope high-rate"},
+		{InterruptAccelHighG, "accelerometer high-g"},
+		{InterruptAccelAnyMotion, "accelerometer any-motion"},
+		{InterruptAccelNoMotion, "accelerometer no-motion"},
+	}
+
+	var parts []string
+	for _, name := range names {
+		if i&name.interrupt != 0 {
+			parts = append(parts, name.name)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "none"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func newInterruptConfig(regs []byte) *InterruptConfig {
+	accIntSettings := regs[bno055AccIntSettings-bno055IntMsk]
+	accNmSet := regs[bno055AccNmSet-bno055IntMsk]
+	gyrIntSetting := regs[bno055GyroIntSetting-bno055IntMsk]
+	gyrAmSet := regs[bno055GyroAmSet-bno055IntMsk]
+
+	highRateAxis := func(reg byte) GyroHighRateAxis {
+		set := regs[reg-bno055IntMsk]
+
+		return GyroHighRateAxis{
+			Threshold:  set & 0x1F,
+			Hysteresis: (set >> 5) & 0x03,
+			Duration:   regs[reg+1-bno055IntMsk],
+		}
+	}
+
+	interruptConfig := &InterruptConfig{
+		Pin:             Interrupts(regs[0]),
+		Enabled:         Interrupts(regs[bno055IntEn-bno055IntMsk]),
+		AccelMotionAxes: Axes(accIntSettings>>2) & AxesAll,
+		AccelAnyMotion: AccelAnyMotion{
+			Threshold: regs[bno055AccAmThres-bno055IntMsk],
+			Duration:  accIntSettings & 0x03,
+		},
+		AccelNoMotion: AccelNoMotion{
+			Threshold:  regs[bno055AccNmThres-bno055IntMsk],
+			Duration:   (accNmSet >> 1) & 0x3F,
+			SlowMotion: accNmSet&0x01 == 0,
+		},
+		AccelHighG: AccelHighG{
+			Axes:      Axes(accIntSettings>>5) & AxesAll,
+			Threshold: regs[bno055AccHgThres-bno055IntMsk],
+			Duration:  regs[bno055AccHgDuration-bno055IntMsk],
+		},
+		GyroAnyMotion: GyroAnyMotion{
+			Axes:          Axes(gyrIntSetting) & AxesAll,
+			Threshold:     regs[bno055GyroAmThres-bno055IntMsk] & 0x7F,
+			Samples:       gyrAmSet & 0x03,
+			AwakeDuration: (gyrAmSet >> 2) & 0x03,
+			Unfiltered:    gyrIntSetting&0x40 != 0,
+		},
+		GyroHighRate: GyroHighRate{
+			Axes:       Axes(gyrIntSetting>>3) & AxesAll,
+			X:          highRateAxis(bno055GyroHrXSet),
+			Y:          highRateAxis(bno055GyroHrYSet),
+			Z:          highRateAxis(bno055GyroHrZSet),
+			Unfiltered: gyrIntSetting&0x80 != 0,
+		},
+	}
+
+	return interruptConfig
+}
+
+// Returns the values of the registers from INT_MSK to GYR_AM_SET
+func (c *InterruptConfig) registers() ([]byte, error) {
+	switch {
+	case c.AccelAnyMotion.Duration > 0x03:
+		return nil, fmt.Errorf("bno055: any-motion duration %d out of range 0-3", c.AccelAnyMotion.Duration)
+	case c.AccelNoMotion.Duration > 0x3F:
+		return nil, fmt.Errorf("bno055: no-motion duration %d out of range 0-63", c.AccelNoMotion.Duration)
+	case c.GyroAnyMotion.Threshold > 0x7F:
+		return nil, fmt.Errorf("bno055: gyroscope any-motion threshold %d out of range 0-127", c.GyroAnyMotion.Threshold)
+	case c.GyroAnyMotion.Samples > 0x03:
+		return nil, fmt.Errorf("bno055: gyroscope any-motion samples %d out of range 0-3", c.GyroAnyMotion.Samples)
+	case c.GyroAnyMotion.AwakeDuration > 0x03:
+		return nil, fmt.Errorf("bno055: gyroscope any-motion awake duration %d out of range 0-3", c.GyroAnyMotion.AwakeDuration)
+	}
+
+	regs := make([]byte, bno055GyroAmSet-bno055IntMsk+1)
+
+	regs[0] = byte(c.Pin)
+	regs[bno055IntEn-bno055IntMsk] = byte(c.Enabled)
+
+	regs[bno055AccAmThres-bno055IntMsk] = c.AccelAnyMotion.Threshold
+	regs[bno055AccIntSettings-bno055IntMsk] = byte(c.AccelHighG.Axes&AxesAll)<<5 | byte(c.AccelMotionAxes&AxesAll)<<2 | c.AccelAnyMotion.Duration
+	regs[bno055AccHgDuration-bno055IntMsk] = c.AccelHighG.Duration
+	regs[bno055AccHgThres-bno055IntMsk] = c.AccelHighG.Threshold
+	regs[bno055AccNmThres-bno055IntMsk] = c.AccelNoMotion.Threshold
+
+	// Bit 0 set selects no-motion, cleared selects slow-motion
+	accNmSet := c.AccelNoMotion.Duration << 1
+	if !c.AccelNoMotion.SlowMotion {
+		accNmSet |= 0x01
+	}
+
+	regs[bno055AccNmSet-bno055IntMsk] = accNmSet
+
+	gyrIntSetting := byte(c.GyroHighRate.Axes&AxesAll)<<3 | byte(c.GyroAnyMotion.Axes&AxesAll)
+	if c.GyroAnyMotion.Unfiltered {
+		gyrIntSetting |= 0x40
+	}
+
+	if c.GyroHighRate.Unfiltered {
+		gyrIntSetting |= 0x80
+	}
+
+	regs[bno055GyroIntSetting-bno055IntMsk] = gyrIntSetting
+
+	for i, axis := range []GyroHighRateAxis{c.GyroHighRate.X, c.GyroHighRate.Y, c.GyroHighRate.Z} {
+		if axis.Threshold > 0x1F || axis.Hysteresis > 0x03 {
+			return nil, fmt.Errorf("bno055: gyroscope high-rate setting of axis %c out of range", 'X'+i)
+		}
+
+		reg := bno055GyroHrXSet + 2*i - bno055IntMsk
+		regs[reg] = axis.Hysteresis<<5 | axis.Threshold
+		regs[reg+1] = axis.Duration
+	}
+
+	regs[bno055GyroAmThres-bno055IntMsk] = c.GyroAnyMotion.Threshold
+	regs[bno055GyroAmSet-bno055IntMsk] = c.GyroAnyMotion.AwakeDuration<<2 | c.GyroAnyMotion.Samples
+
+	return regs, nil
+}
+
+func (s *Sensor) InterruptConfig() (*InterruptConfig, error) {
+	return s.InterruptConfigContext(context.Background())
+}
+
+func (s *Sensor) InterruptConfigContext(ctx context.Context) (*InterruptConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	regs := make([]byte, bno055GyroAmSet-bno055IntMsk+1)
+	err := s.readBufferPage(ctx, Page1, bno055IntMsk, regs)
+	if err != nil {
+		return nil, err
+	}
+
+	return newInterruptConfig(regs), nil
+}
+
+func (s *Sensor) SetInterruptConfig(config *InterruptConfig) error {
+	return s.SetInterruptConfigContext(context.Background(), config)
+}
+
+// SetInterruptConfigContext writes the interrupt registers in CONFIG mode.
+func (s *Sensor) SetInterruptConfigContext(ctx context.Context, config *InterruptConfig) error {
+	regs, err := config.registers()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.inConfigMode(ctx, func() error {
+		return s.writeBufferPage(ctx, Page1, bno055IntMsk, regs)
+	})
+}
+
+func (s *Sensor) InterruptStatus() (Interrupts, error) {
+	return s.InterruptStatusContext(context.Background())
+}
+
+// InterruptStatusContext reads INT_STA. The status stays set until
+// ResetInterrupts is called.
+func (s *Sensor) InterruptStatusContext(ctx context.Context) (Interrupts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, err := s.read(ctx, bno055IntrStat)
+	if err != nil {
+		return 0, err
+	}
+
+	return Interrupts(status), nil
+}
+
+func (s *Sensor) ResetInterrupts() error {
+	return s.ResetInterruptsContext(context.Background())
+}
+
+// ResetInterruptsContext clears INT_STA and releases the INT pin through
+// the RST_INT bit of SYS_TRIGGER. The clock selection is written along with
+// it, as SYS_TRIGGER is write-only.
+func (s *Sensor) ResetInterruptsContext(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.write(ctx, bno055SysTrigger, s.clkSel|SysTriggerRstInt)
+}
+
+// SetEdgeSource sets the line WaitForInterrupt waits on.
+func (s *Sensor) SetEdgeSource(source EdgeSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.edges = source
+}
+
+// WaitForInterrupt blocks until the edge source reports an edge of the INT
+// pin, then reads INT_STA and resets the interrupts, so the pin can fire
+// again. The pin is latched, so pending interrupts should be reset before
+// the first wait.
+func (s *Sensor) WaitForInterrupt(ctx context.Context) (Interrupts, error) {
+	s.mu.Lock()
+	source := s.edges
+	s.mu.Unlock()
+
+	if source == nil {
+		return 0, ErrNoEdgeSource
+	}
+
+	err := source.WaitForEdge(ctx)
+	if err != nil {
+		return 0, wrapCanceled(ctx, "wait for interrupt", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, err := s.read(ctx, bno055IntrStat)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.write(ctx, bno055SysTrigger, s.clkSel|SysTriggerRstInt)
+	if err != nil {
+		return 0, err
+	}
+
+	return Interrupts(status), nil
+}
@@ -0,0 +1,172 @@
+package bno055_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kpeu3i/bno055"
+	"github.com/kpeu3i/bno055/bnotest"
+)
+
+func TestInterruptConfig(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	config := &bno055.InterruptConfig{
+		Enabled:         bno055.InterruptAccelHighG | bno055.InterruptGyroAnyMotion,
+		Pin:             bno055.InterruptAccelHighG,
+		AccelMotionAxes: bno055.AxisX | bno055.AxisZ,
+		AccelAnyMotion:  bno055.AccelAnyMotion{Threshold: 20, Duration: 2},
+		AccelNoMotion:   bno055.AccelNoMotion{Threshold: 10, Duration: 21},
+		AccelHighG:      bno055.AccelHighG{Axes: bno055.AxisY, Threshold: 0xC0, Duration: 0x0F},
+		GyroAnyMotion: bno055.GyroAnyMotion{
+			Axes:          bno055.AxesAll,
+			Threshold:     4,
+			Samples:       2,
+			AwakeDuration: 1,
+			Unfiltered:    true,
+		},
+		GyroHighRate: bno055.GyroHighRate{
+			Axes: bno055.AxisZ,
+			X:    bno055.GyroHighRateAxis{Threshold: 1, Hysteresis: 2, Duration: 25},
+			Y:    bno055.GyroHighRateAxis{Threshold: 31, Hysteresis: 3},
+			Z:    bno055.GyroHighRateAxis{Hysteresis: 1, Duration: 7},
+		},
+	}
+
+	err := sensor.SetInterruptConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	regs := []struct {
+		name string
+		reg  byte
+		want byte
+	}{
+		{"ACC_INT_Settings", bno055.RegAccIntSettings, 0x56},
+		{"ACC_NM_SET", bno055.RegAccNMSet, 0x2B},
+		{"GYR_INT_SETTING", bno055.RegGyrIntSetting, 0x67},
+		{"GYR_HR_X_SET", bno055.RegGyrHRXSet, 0x41},
+		{"GYR_DUR_X", bno055.RegGyrDurX, 25},
+		{"GYR_HR_Y_SET", bno055.RegGyrHRYSet, 0x7F},
+		{"GYR_HR_Z_SET", bno055.RegGyrHRZSet, 0x20},
+		{"GYR_AM_SET", bno055.RegGyrAMSet, 0x06},
+	}
+
+	for _, reg := range regs {
+		if val := device.Register(bnotest.Page1, reg.reg); val != reg.want {
+			t.Errorf("%s = 0x%02X, want 0x%02X", reg.name, val, reg.want)
+		}
+	}
+
+	interruptConfig, err := sensor.InterruptConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *interruptConfig != *config {
+		t.Fatalf("InterruptConfig = %+v, want %+v", *interruptConfig, *config)
+	}
+
+	// Slow-motion clears bit 0 of ACC_NM_SET
+	config.AccelNoMotion.SlowMotion = true
+
+	err = sensor.SetInterruptConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val := device.Register(bnotest.Page1, bno055.RegAccNMSet); val != 0x2A {
+		t.Fatalf("ACC_NM_SET = 0x%02X with slow-motion, want 0x2A", val)
+	}
+
+	interruptConfig, err = sensor.InterruptConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !interruptConfig.AccelNoMotion.SlowMotion {
+		t.Fatal("slow-motion read back as no-motion")
+	}
+}
+
+func TestWaitForInterrupt(t *testing.T) {
+	// The simulator only stores CLK_SEL in CONFIG mode
+	sensor, device := newTestSensor(t, bno055.WithOperationMode(bno055.OperationModeConfig))
+
+	err := sensor.UseExternalCrystal(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pin := bnotest.NewPin()
+	sensor.SetEdgeSource(pin)
+
+	device.SetInterruptStatus(byte(bno055.InterruptAccelAnyMotion | bno055.InterruptGyroHighRate))
+	pin.Trigger()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	status, err := sensor.WaitForInterrupt(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status != bno055.InterruptAccelAnyMotion|bno055.InterruptGyroHighRate {
+		t.Fatalf("status = %s, want accelerometer any-motion and gyroscope high-rate", status)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegIntSta); val != 0 {
+		t.Fatalf("INT_STA = 0x%02X after the wait, want 0x00", val)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegSysTrigger); val != bno055.SysTriggerClkSel {
+		t.Fatalf("SYS_TRIGGER = 0x%02X after RST_INT, want the external crystal kept", val)
+	}
+}
+
+func TestWaitForInterruptCanceled(t *testing.T) {
+	sensor, _ := newTestSensor(t)
+
+	_, err := sensor.WaitForInterrupt(context.Background())
+	if !errors.Is(err, bno055.ErrNoEdgeSource) {
+		t.Fatalf("WaitForInterrupt error = %v, want %v", err, bno055.ErrNoEdgeSource)
+	}
+
+	sensor.SetEdgeSource(bnotest.NewPin())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = sensor.WaitForInterrupt(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForInterrupt error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestResetInterruptsKeepsExternalCrystal(t *testing.T) {
+	sensor, device := newTestSensor(t, bno055.WithOperationMode(bno055.OperationModeConfig))
+
+	err := sensor.UseExternalCrystal(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	device.SetInterruptStatus(byte(bno055.InterruptAccelHighG))
+
+	err = sensor.ResetInterrupts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegIntSta); val != 0 {
+		t.Fatalf("INT_STA = 0x%02X after reset, want 0x00", val)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegSysTrigger); val != bno055.SysTriggerClkSel {
+		t.Fatalf("SYS_TRIGGER = 0x%02X after RST_INT, want the external crystal kept", val)
+	}
+}
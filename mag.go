@@ -0,0 +1,116 @@
+package bno055
+
+import (
+	"context"
+	"fmt"
+)
+
+// MagDataRate is the output data rate of the magnetometer.
+type MagDataRate byte
+
+const (
+	MagDataRate2Hz  MagDataRate = 0x00
+	MagDataRate6Hz  MagDataRate = 0x01
+	MagDataRate8Hz  MagDataRate = 0x02
+	MagDataRate10Hz MagDataRate = 0x03
+	MagDataRate15Hz MagDataRate = 0x04
+	MagDataRate20Hz MagDataRate = 0x05
+	MagDataRate25Hz MagDataRate = 0x06
+	MagDataRate30Hz MagDataRate = 0x07
+)
+
+// MagOperationMode trades the accuracy of the magnetometer for power
+// (see section 3.5.4 of the datasheet).
+type MagOperationMode byte
+
+const (
+	MagOperationModeLowPower        MagOperationMode = 0x00
+	MagOperationModeRegular         MagOperationMode = 0x01
+	MagOperationModeEnhancedRegular MagOperationMode = 0x02
+	MagOperationModeHighAccuracy    MagOperationMode = 0x03
+)
+
+// MagPowerMode is the power mode of the magnetometer itself.
+type MagPowerMode byte
+
+const (
+	MagPowerModeNormal  MagPowerMode = 0x00
+	MagPowerModeSleep   MagPowerMode = 0x01
+	MagPowerModeSuspend MagPowerMode = 0x02
+	MagPowerModeForce   MagPowerMode = 0x03
+)
+
+// MagConfig maps to the MAG_Config register on page 1. The fusion modes
+// set the magnetometer themselves, so it can only be changed in the
+// non-fusion modes. The reset value is 20Hz, regular and force mode.
+type MagConfig struct {
+	DataRate      MagDataRate      `json:"data_rate"`
+	OperationMode MagOperationMode `json:"operation_mode"`
+	PowerMode     MagPowerMode     `json:"power_mode"`
+}
+
+func newMagConfig(magConfig byte) *MagConfig {
+	config := &MagConfig{
+		DataRate:      MagDataRate(magConfig & MagConfigDataRate),
+		OperationMode: MagOperationMode((magConfig & MagConfigOprMode) >> 3),
+		PowerMode:     MagPowerMode((magConfig & MagConfigPwrMode) >> 5),
+	}
+
+	return config
+}
+
+// Returns the MAG_Config value for the config
+func (c *MagConfig) register() (byte, error) {
+	if c.DataRate > MagDataRate30Hz {
+		return 0, fmt.Errorf("bno055: invalid magnetometer data rate 0x%02X", byte(c.DataRate))
+	}
+
+	if c.OperationMode > MagOperationModeHighAccuracy {
+		return 0, fmt.Errorf("bno055: invalid magnetometer operation mode 0x%02X", byte(c.OperationMode))
+	}
+
+	if c.PowerMode > MagPowerModeForce {
+		return 0, fmt.Errorf("bno055: invalid magnetometer power mode 0x%02X", byte(c.PowerMode))
+	}
+
+	return byte(c.PowerMode)<<5 | byte(c.OperationMode)<<3 | byte(c.DataRate), nil
+}
+
+func (s *Sensor) MagConfig() (*MagConfig, error) {
+	return s.MagConfigContext(context.Background())
+}
+
+func (s *Sensor) MagConfigContext(ctx context.Context) (*MagConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	magConfig, err := s.readPage(ctx, Page1, bno055MagConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMagConfig(magConfig), nil
+}
+
+func (s *Sensor) SetMagConfig(config *MagConfig) error {
+	return s.SetMagConfigContext(context.Background(), config)
+}
+
+// SetMagConfigContext writes MAG_Config in CONFIG mode. It returns
+// ErrFusionControlled if the current operation mode is a fusion mode.
+func (s *Sensor) SetMagConfigContext(ctx context.Context, config *MagConfig) error {
+	magConfig, err := config.register()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.checkNotFusion("magnetometer configuration")
+	if err != nil {
+		return err
+	}
+
+	return s.writeConfig(ctx, Page1, bno055MagConfig, magConfig)
+}
@@ -0,0 +1,167 @@
+package bno055
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OperationMode selects which sensors are enabled and whether the fusion
+// algorithm runs (see section 3.3 of the datasheet).
+type OperationMode byte
+
+const (
+	OperationModeConfig     OperationMode = bno055OperationModeConfig
+	OperationModeAccOnly    OperationMode = bno055OperationModeAcconly
+	OperationModeMagOnly    OperationMode = bno055OperationModeMagonly
+	OperationModeGyroOnly   OperationMode = bno055OperationModeGyronly
+	OperationModeAccMag     OperationMode = bno055OperationModeAccmag
+	OperationModeAccGyro    OperationMode = bno055OperationModeAccgyro
+	OperationModeMagGyro    OperationMode = bno055OperationModeMaggyro
+	OperationModeAMG        OperationMode = bno055OperationModeAmg
+	OperationModeIMUPlus    OperationMode = bno055OperationModeImuplus
+	OperationModeCompass    OperationMode = bno055OperationModeCompass
+	OperationModeM4G        OperationMode = bno055OperationModeM4g
+	OperationModeNDOFFMCOff OperationMode = bno055OperationModeNdofFmcOff
+	OperationModeNDOF       OperationMode = bno055OperationModeNdof
+)
+
+// Outputs is a set of data outputs of the sensor.
+type Outputs uint8
+
+const (
+	OutputAccelerometer Outputs = 1 << iota
+	OutputMagnetometer
+	OutputGyroscope
+	OutputEuler
+	OutputQuaternion
+	OutputLinearAcceleration
+	OutputGravity
+
+	outputFusion = OutputEuler | OutputQuaternion | OutputLinearAcceleration | OutputGravity
+)
+
+var (
+	ErrOutputUnavailable = errors.New("bno055: output is not available")
+	ErrFusionControlled  = errors.New("bno055: setting is controlled by the fusion algorithm")
+)
+
+var operationModes = []struct {
+	name    string
+	outputs Outputs
+}{
+	OperationModeConfig:     {"CONFIG", 0},
+	OperationModeAccOnly:    {"ACCONLY", OutputAccelerometer},
+	OperationModeMagOnly:    {"MAGONLY", OutputMagnetometer},
+	OperationModeGyroOnly:   {"GYROONLY", OutputGyroscope},
+	OperationModeAccMag:     {"ACCMAG", OutputAccelerometer | OutputMagnetometer},
+	OperationModeAccGyro:    {"ACCGYRO", OutputAccelerometer | OutputGyroscope},
+	OperationModeMagGyro:    {"MAGGYRO", OutputMagnetometer | OutputGyroscope},
+	OperationModeAMG:        {"AMG", OutputAccelerometer | OutputMagnetometer | OutputGyroscope},
+	OperationModeIMUPlus:    {"IMUPLUS", OutputAccelerometer | OutputGyroscope | outputFusion},
+	OperationModeCompass:    {"COMPASS", OutputAccelerometer | OutputMagnetometer | outputFusion},
+	OperationModeM4G:        {"M4G", OutputAccelerometer | OutputMagnetometer | outputFusion},
+	OperationModeNDOFFMCOff: {"NDOF_FMC_OFF", OutputAccelerometer | OutputMagnetometer | OutputGyroscope | outputFusion},
+	OperationModeNDOF:       {"NDOF", OutputAccelerometer | OutputMagnetometer | OutputGyroscope | outputFusion},
+}
+
+func (m OperationMode) IsValid() bool {
+	return int(m) < len(operationModes)
+}
+
+// Outputs returns the data outputs that are valid in the mode.
+func (m OperationMode) Outputs() Outputs {
+	if !m.IsValid() {
+		return 0
+	}
+
+	return operationModes[m].outputs
+}
+
+// IsFusion reports whether the fusion algorithm runs in the mode.
+func (m OperationMode) IsFusion() bool {
+	return m.Outputs()&outputFusion != 0
+}
+
+func (m OperationMode) String() string {
+	if !m.IsValid() {
+		return fmt.Sprintf("OperationMode(0x%02X)", byte(m))
+	}
+
+	return operationModes[m].name
+}
+
+func (o Outputs) Has(outputs Outputs) bool {
+	return o&outputs == outputs
+}
+
+func (o Outputs) String() string {
+	names := []string{"accelerometer", "magnetometer", "gyroscope", "euler", "quaternion", "linear acceleration", "gravity"}
+
+	var parts []string
+	for i, name := range names {
+		if o&(1<<uint(i)) != 0 {
+			parts = append(parts, name)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "none"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// OperationMode returns the operation mode last set on the sensor.
+func (s *Sensor) OperationMode() OperationMode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return OperationMode(s.opMode)
+}
+
+func (s *Sensor) SetOperationMode(mode OperationMode) error {
+	return s.SetOperationModeContext(context.Background(), mode)
+}
+
+// SetOperationModeContext switches the operation mode. Switching between two
+// modes other than CONFIG goes through CONFIG mode, and the mode is read back
+// once the switching time has passed.
+func (s *Sensor) SetOperationModeContext(ctx context.Context, mode OperationMode) error {
+	if !mode.IsValid() {
+		return fmt.Errorf("bno055: invalid operation mode 0x%02X", byte(mode))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.setOperationMode(ctx, byte(mode))
+}
+
+// Returns an error if the output is not valid in the current operation mode,
+// must be called with the lock held
+func (s *Sensor) checkOutput(output Outputs) error {
+	err := s.checkAwake()
+	if err != nil {
+		return err
+	}
+
+	mode := OperationMode(s.opMode)
+	if !mode.Outputs().Has(output) {
+		return fmt.Errorf("%w: no %s data in %s mode", ErrOutputUnavailable, output, mode)
+	}
+
+	return nil
+}
+
+// Returns an error if the fusion algorithm of the current operation mode
+// overwrites the sensor configuration, must be called with the lock held
+func (s *Sensor) checkNotFusion(setting string) error {
+	mode := OperationMode(s.opMode)
+	if mode.IsFusion() {
+		return fmt.Errorf("%w: %s cannot be changed in %s mode", ErrFusionControlled, setting, mode)
+	}
+
+	return nil
+}
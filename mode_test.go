@@ -0,0 +1,57 @@
+package bno055_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kpeu3i/bno055"
+	"github.com/kpeu3i/bno055/bnotest"
+)
+
+func TestEulerOutputUnavailable(t *testing.T) {
+	sensor, _ := newTestSensor(t, bno055.WithOperationMode(bno055.OperationModeAMG))
+
+	_, err := sensor.Euler()
+	if !errors.Is(err, bno055.ErrOutputUnavailable) {
+		t.Fatalf("Euler error = %v, want %v", err, bno055.ErrOutputUnavailable)
+	}
+}
+
+func TestSetOperationMode(t *testing.T) {
+	var modes []byte
+
+	device := bnotest.NewDevice()
+	bus := &hookBus{
+		Device: device,
+		hook: func(page int, reg byte, buff []byte) error {
+			if page == bnotest.Page0 && reg == bno055.RegOprMode {
+				modes = append(modes, buff[0])
+			}
+
+			return nil
+		},
+	}
+
+	sensor, err := bno055.NewSensorFromBus(bus, bno055.WithoutReset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modes = nil
+
+	err = sensor.SetOperationMode(bno055.OperationModeIMUPlus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NDOF to IMU goes through CONFIG
+	want := []byte{byte(bno055.OperationModeConfig), byte(bno055.OperationModeIMUPlus)}
+	if !bytes.Equal(modes, want) {
+		t.Fatalf("OPR_MODE writes = % X, want % X", modes, want)
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeIMUPlus) {
+		t.Fatalf("device in operation mode 0x%02X, want IMU", mode)
+	}
+}
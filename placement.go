@@ -0,0 +1,65 @@
+package bno055
+
+import (
+	"context"
+	"fmt"
+)
+
+// Placement is one of the mounting positions of the chip shown in section
+// 3.4 of the datasheet. P1 is the reset value.
+type Placement byte
+
+const (
+	PlacementP0 Placement = iota
+	PlacementP1
+	PlacementP2
+	PlacementP3
+	PlacementP4
+	PlacementP5
+	PlacementP6
+	PlacementP7
+)
+
+var placements = []struct {
+	config byte
+	sign   byte
+}{
+	PlacementP0: {bno055RemapConfigP0, bno055RemapSignP0},
+	PlacementP1: {bno055RemapConfigP1, bno055RemapSignP1},
+	PlacementP2: {bno055RemapConfigP2, bno055RemapSignP2},
+	PlacementP3: {bno055RemapConfigP3, bno055RemapSignP3},
+	PlacementP4: {bno055RemapConfigP4, bno055RemapSignP4},
+	PlacementP5: {bno055RemapConfigP5, bno055RemapSignP5},
+	PlacementP6: {bno055RemapConfigP6, bno055RemapSignP6},
+	PlacementP7: {bno055RemapConfigP7, bno055RemapSignP7},
+}
+
+func (p Placement) IsValid() bool {
+	return int(p) < len(placements)
+}
+
+// AxisConfig returns the axis remapping of the placement.
+func (p Placement) AxisConfig() (*AxisConfig, error) {
+	if !p.IsValid() {
+		return nil, fmt.Errorf("bno055: invalid placement %d", p)
+	}
+
+	return newAxisConfig(placements[p].config, placements[p].sign), nil
+}
+
+func (p Placement) String() string {
+	return fmt.Sprintf("P%d", byte(p))
+}
+
+func (s *Sensor) RemapAxisPlacement(placement Placement) error {
+	return s.RemapAxisPlacementContext(context.Background(), placement)
+}
+
+func (s *Sensor) RemapAxisPlacementContext(ctx context.Context, placement Placement) error {
+	config, err := placement.AxisConfig()
+	if err != nil {
+		return err
+	}
+
+	return s.RemapAxisContext(ctx, config)
+}
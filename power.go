@@ -0,0 +1,157 @@
+package bno055
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PowerMode selects the power mode of the sensor (see section 3.2 of the datasheet).
+type PowerMode byte
+
+const (
+	PowerModeNormal   PowerMode = bno055PowerModeNormal
+	PowerModeLowPower PowerMode = bno055PowerModeLowpower
+	PowerModeSuspend  PowerMode = bno055PowerModeSuspend
+)
+
+var ErrSuspended = errors.New("bno055: sensor is suspended")
+
+func (m PowerMode) IsValid() bool {
+	return m <= PowerModeSuspend
+}
+
+func (m PowerMode) String() string {
+	switch m {
+	case PowerModeNormal:
+		return "normal"
+	case PowerModeLowPower:
+		return "low power"
+	case PowerModeSuspend:
+		return "suspend"
+	}
+
+	return fmt.Sprintf("PowerMode(0x%02X)", byte(m))
+}
+
+// LowPowerConfig sets when the sensor leaves and re-enters the sleep phase of
+// the low power mode. Only the accelerometer runs while asleep: any-motion
+// wakes the sensor up and no-motion puts it back to sleep (see section 3.2.2).
+//
+// Thresholds are in accelerometer LSB, whose size depends on the accelerometer
+// range (3.91 mg at 2G, 7.81 mg at 4G, 15.63 mg at 8G, 31.25 mg at 16G).
+type LowPowerConfig struct {
+	// Any-motion threshold (ACC_AM_THRES)
+	AnyMotionThreshold uint8
+	// Consecutive samples above the threshold minus one, 0-3 (ACC_INT_Settings)
+	AnyMotionDuration uint8
+	// No-motion threshold (ACC_NM_THRES)
+	NoMotionThreshold uint8
+	// No-motion delay, 0-63 (ACC_NM_SET, see section 4.4.16)
+	NoMotionDuration uint8
+}
+
+// PowerMode returns the power mode last set on the sensor.
+func (s *Sensor) PowerMode() PowerMode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return PowerMode(s.pwrMode)
+}
+
+func (s *Sensor) SetPowerMode(mode PowerMode) error {
+	return s.SetPowerModeContext(context.Background(), mode)
+}
+
+func (s *Sensor) SetPowerModeContext(ctx context.Context, mode PowerMode) error {
+	if !mode.IsValid() {
+		return fmt.Errorf("bno055: invalid power mode 0x%02X", byte(mode))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.setPowerMode(ctx, byte(mode))
+}
+
+func (s *Sensor) SetLowPowerMode(config *LowPowerConfig) error {
+	return s.SetLowPowerModeContext(context.Background(), config)
+}
+
+// SetLowPowerModeContext writes the wake-up and sleep conditions and switches
+// the sensor to low power mode.
+func (s *Sensor) SetLowPowerModeContext(ctx context.Context, config *LowPowerConfig) error {
+	if config.AnyMotionDuration > 0x03 {
+		return fmt.Errorf("bno055: any-motion duration %d out of range 0-3", config.AnyMotionDuration)
+	}
+
+	if config.NoMotionDuration > 0x3F {
+		return fmt.Errorf("bno055: no-motion duration %d out of range 0-63", config.NoMotionDuration)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.inConfigMode(ctx, func() error {
+		settings, err := s.readPage(ctx, Page1, bno055AccIntSettings)
+		if err != nil {
+			return err
+		}
+
+		// Keep the high-g axes, enable any-motion and no-motion on all axes
+		settings = settings&0xE0 | 0x1C | config.AnyMotionDuration
+
+		err = s.writePage(ctx, Page1, bno055AccIntSettings, settings)
+		if err != nil {
+			return err
+		}
+
+		err = s.writePage(ctx, Page1, bno055AccAmThres, config.AnyMotionThreshold)
+		if err != nil {
+			return err
+		}
+
+		err = s.writePage(ctx, Page1, bno055AccNmThres, config.NoMotionThreshold)
+		if err != nil {
+			return err
+		}
+
+		// Bit 0 set selects no-motion instead of slow-motion
+		err = s.writePage(ctx, Page1, bno055AccNmSet, config.NoMotionDuration<<1|0x01)
+		if err != nil {
+			return err
+		}
+
+		err = s.write(ctx, bno055PwrMode, bno055PowerModeLowpower)
+		if err != nil {
+			return err
+		}
+
+		s.pwrMode = bno055PowerModeLowpower
+
+		return nil
+	})
+}
+
+// Writes PWR_MODE in CONFIG mode and restores the operation mode
+func (s *Sensor) setPowerMode(ctx context.Context, mode byte) error {
+	return s.inConfigMode(ctx, func() error {
+		err := s.write(ctx, bno055PwrMode, mode)
+		if err != nil {
+			return err
+		}
+
+		s.pwrMode = mode
+
+		return nil
+	})
+}
+
+// Data registers are not updated in suspend mode, must be called with the lock held
+func (s *Sensor) checkAwake() error {
+	if s.pwrMode == bno055PowerModeSuspend {
+		return fmt.Errorf("%w: data is not updated until it is woken up", ErrSuspended)
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package bno055
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ProfileVersion is the version written by Snapshot. Restore rejects
+// profiles with other versions.
+const ProfileVersion = 1
+
+var ErrProfileVersion = errors.New("bno055: unsupported profile version")
+
+// Profile is a serializable snapshot of the writable configuration of a
+// sensor: the page 0 unit, mode, axis, offset and SIC registers and the
+// page 1 sensor settings.
+type Profile struct {
+	Version            int                `json:"version"`
+	OperationMode      OperationMode      `json:"operation_mode"`
+	PowerMode          PowerMode          `json:"power_mode"`
+	Units              Units              `json:"units"`
+	AxisConfig         *AxisConfig        `json:"axis_config"`
+	ExternalCrystal    bool               `json:"external_crystal"`
+	TemperatureSource  TemperatureSource  `json:"temperature_source"`
+	CalibrationOffsets CalibrationOffsets `json:"calibration_offsets"`
+	SoftIronMatrix     *SICMatrix         `json:"soft_iron_matrix"`
+	Accel              *AccelConfig       `json:"accel"`
+	Gyro               *GyroConfig        `json:"gyro"`
+	Mag                *MagConfig         `json:"mag"`
+}
+
+// Names of the calibration offsets in a profile, in register order
+// (see section 3.6.4 of the datasheet)
+var profileOffsetNames = []string{
+	"accel_x", "accel_y", "accel_z",
+	"mag_x", "mag_y", "mag_z",
+	"gyro_x", "gyro_y", "gyro_z",
+	"accel_radius", "mag_radius",
+}
+
+type profileAlias Profile
+
+// JSON form of a profile, with the calibration offsets as named signed
+// values instead of base64
+type profileJSON struct {
+	*profileAlias
+	CalibrationOffsets map[string]int16 `json:"calibration_offsets"`
+}
+
+// RestoreReport lists the settings Restore changed, named as in Config
+// (for example "units" or "calibration offsets"), and the sensor settings
+// of the profile it skipped because the fusion mode of the profile sets them.
+type RestoreReport struct {
+	Changed []string
+	Skipped []string
+}
+
+// ReadProfile decodes a JSON profile.
+func ReadProfile(r io.Reader) (*Profile, error) {
+	profile := &Profile{}
+
+	err := json.NewDecoder(r).Decode(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile.Version != ProfileVersion {
+		return nil, fmt.Errorf("%w: %d", ErrProfileVersion, profile.Version)
+	}
+
+	return profile, nil
+}
+
+// Write encodes the profile as indented JSON.
+func (p *Profile) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(p)
+}
+
+// MarshalJSON encodes the profile with the calibration offsets as named values.
+func (p Profile) MarshalJSON() ([]byte, error) {
+	profile := profileJSON{
+		profileAlias: (*profileAlias)(&p),
+	}
+
+	if p.CalibrationOffsets != nil {
+		if len(p.CalibrationOffsets) != 2*len(profileOffsetNames) {
+			return nil, fmt.Errorf("bno055: calibration offsets must be 22 bytes, got %d", len(p.CalibrationOffsets))
+		}
+
+		profile.CalibrationOffsets = make(map[string]int16)
+		for i, name := range profileOffsetNames {
+			profile.CalibrationOffsets[name] = int16(binary.LittleEndian.Uint16(p.CalibrationOffsets[2*i:]))
+		}
+	}
+
+	return json.Marshal(profile)
+}
+
+// UnmarshalJSON decodes a profile encoded by MarshalJSON. The calibration
+// offsets must either be null or contain every offset and radius.
+func (p *Profile) UnmarshalJSON(data []byte) error {
+	profile := profileJSON{
+		profileAlias: (*profileAlias)(p),
+	}
+
+	err := json.Unmarshal(data, &profile)
+	if err != nil {
+		return err
+	}
+
+	p.CalibrationOffsets = nil
+	if profile.CalibrationOffsets == nil {
+		return nil
+	}
+
+	offsets := make(CalibrationOffsets, 2*len(profileOffsetNames))
+	for i, name := range profileOffsetNames {
+		val, ok := profile.CalibrationOffsets[name]
+		if !ok {
+			return fmt.Errorf("bno055: calibration offset %q missing from profile", name)
+		}
+
+		binary.LittleEndian.PutUint16(offsets[2*i:], uint16(val))
+	}
+
+	if len(profile.CalibrationOffsets) != len(profileOffsetNames) {
+		return fmt.Errorf("bno055: unknown calibration offsets in profile, want %v", profileOffsetNames)
+	}
+
+	p.CalibrationOffsets = offsets
+
+	return nil
+}
+
+// Returns the config restoring the profile and the names of the skipped
+// settings. The fusion modes set the page 1 sensor settings themselves, so
+// they are only restored in non-fusion modes.
+func (p *Profile) config() (*Config, []string) {
+	operationMode := p.OperationMode
+	powerMode := p.PowerMode
+	units := p.Units
+	externalCrystal := p.ExternalCrystal
+	temperatureSource := p.TemperatureSource
+
+	config := &Config{
+		OperationMode:      &operationMode,
+		PowerMode:          &powerMode,
+		Units:              &units,
+		AxisConfig:         p.AxisConfig,
+		ExternalCrystal:    &externalCrystal,
+		TemperatureSource:  &temperatureSource,
+		CalibrationOffsets: p.CalibrationOffsets,
+		SoftIronMatrix:     p.SoftIronMatrix,
+	}
+
+	if !operationMode.IsFusion() {
+		config.Accel = p.Accel
+		config.Gyro = p.Gyro
+		config.Mag = p.Mag
+
+		return config, nil
+	}
+
+	var skipped []string
+
+	if p.Accel != nil {
+		skipped = append(skipped, "accelerometer")
+	}
+
+	if p.Mag != nil {
+		skipped = append(skipped, "magnetometer")
+	}
+
+	if p.Gyro != nil {
+		skipped = append(skipped, "gyroscope")
+	}
+
+	return config, skipped
+}
+
+func (s *Sensor) Snapshot() (*Profile, error) {
+	return s.SnapshotContext(context.Background())
+}
+
+// SnapshotContext reads the configuration of the sensor in CONFIG mode.
+func (s *Sensor) SnapshotContext(ctx context.Context) (*Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// UNIT_SEL to MAG_RADIUS_MSB
+	page0 := make([]byte, bno055MagRadiusMsb-bno055UnitSel+1)
+	// ACC_Config to GYR_Config_1
+	page1 := make([]byte, bno055GyroConfig1-bno055AccConfig+1)
+
+	err := s.inConfigMode(ctx, func() error {
+		err := s.readBuffer(ctx, bno055UnitSel, page0)
+		if err != nil {
+			return err
+		}
+
+		return s.readBufferPage(ctx, Page1, bno055AccConfig, page1)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reg := func(reg byte) []byte {
+		return page0[reg-bno055UnitSel:]
+	}
+
+	profile := &Profile{
+		Version:            ProfileVersion,
+		OperationMode:      OperationMode(s.opMode),
+		PowerMode:          PowerMode(reg(bno055PwrMode)[0] & 0x03),
+		Units:              newUnits(reg(bno055UnitSel)[0]),
+		AxisConfig:         newAxisConfig(reg(bno055AxisMapConfig)[0], reg(bno055AxisMapSign)[0]),
+		ExternalCrystal:    s.clkSel&SysTriggerClkSel != 0,
+		TemperatureSource:  TemperatureSource(reg(bno055TempSource)[0] & 0x01),
+		CalibrationOffsets: CalibrationOffsets(append([]byte(nil), reg(bno055AccelOffsetXLsb)[:22]...)),
+		SoftIronMatrix:     newSICMatrix(reg(bno055SicMatrix0Lsb)),
+		Accel:              newAccelConfig(page1[bno055AccConfig-bno055AccConfig]),
+		Mag:                newMagConfig(page1[bno055MagConfig-bno055AccConfig]),
+		Gyro:               newGyroConfig(page1[bno055GyroConfig0-bno055AccConfig], page1[bno055GyroConfig1-bno055AccConfig]),
+	}
+
+	return profile, nil
+}
+
+func (s *Sensor) Restore(profile *Profile) (*RestoreReport, error) {
+	return s.RestoreContext(context.Background(), profile)
+}
+
+// RestoreContext applies the profile like ApplyContext and reports the
+// settings that differed and the ones that were skipped.
+func (s *Sensor) RestoreContext(ctx context.Context, profile *Profile) (*RestoreReport, error) {
+	if profile.Version != ProfileVersion {
+		return nil, fmt.Errorf("%w: %d", ErrProfileVersion, profile.Version)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config, skipped := profile.config()
+
+	changed, err := s.apply(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RestoreReport{
+		Changed: changed,
+		Skipped: skipped,
+	}
+
+	return report, nil
+}
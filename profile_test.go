@@ -0,0 +1,116 @@
+package bno055_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kpeu3i/bno055"
+)
+
+func TestProfileRoundTrip(t *testing.T) {
+	sensor, _ := newTestSensor(t)
+
+	err := sensor.Calibrate(testOffsets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := sensor.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = profile.Write(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), `"accel_y": -1`) {
+		t.Fatalf("calibration offsets not written as named values:\n%s", buf.String())
+	}
+
+	read, err := bno055.ReadProfile(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(read, profile) {
+		t.Fatalf("ReadProfile = %+v, want %+v", read, profile)
+	}
+}
+
+func TestRestoreSkipsFusionSettings(t *testing.T) {
+	sensor, _ := newTestSensor(t)
+
+	profile, err := sensor.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := sensor.Restore(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Changed) != 0 {
+		t.Fatalf("Changed = %v restoring a fresh snapshot, want none", report.Changed)
+	}
+
+	want := []string{"accelerometer", "magnetometer", "gyroscope"}
+	if !reflect.DeepEqual(report.Skipped, want) {
+		t.Fatalf("Skipped = %v, want %v", report.Skipped, want)
+	}
+}
+
+func TestReadProfileCalibrationOffsets(t *testing.T) {
+	tests := []struct {
+		offsets string
+		ok      bool
+	}{
+		{`null`, true},
+		{`{}`, false},
+		{`{"accel_x": 1, "accel_y": 2, "accel_z": 3}`, false},
+		{`"AQD//wMABAAFAAYABwAIAAkA6APgAQ=="`, false},
+	}
+
+	for _, test := range tests {
+		data := `{"version": 1, "calibration_offsets": ` + test.offsets + `}`
+
+		profile, err := bno055.ReadProfile(strings.NewReader(data))
+		if test.ok != (err == nil) {
+			t.Errorf("ReadProfile with offsets %s: error = %v", test.offsets, err)
+			continue
+		}
+
+		if err == nil && profile.CalibrationOffsets != nil {
+			t.Errorf("ReadProfile with offsets %s = % X, want nil", test.offsets, []byte(profile.CalibrationOffsets))
+		}
+	}
+}
+
+func TestCalibrationOffsetsJSON(t *testing.T) {
+	// Outside profiles, the offsets keep the encoding of a byte slice
+	data, err := json.Marshal(testOffsets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `"AQD//wMABAAFAAYABwAIAAkA6APgAQ=="` {
+		t.Fatalf("json.Marshal(offsets) = %s, want base64", data)
+	}
+
+	var offsets bno055.CalibrationOffsets
+
+	err = json.Unmarshal(data, &offsets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(offsets, testOffsets) {
+		t.Fatalf("json.Unmarshal = % X, want % X", []byte(offsets), []byte(testOffsets))
+	}
+}
@@ -0,0 +1,320 @@
+package bno055
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Page selects one of the two register maps (see section 4.2 of the datasheet).
+type Page byte
+
+const (
+	Page0 Page = 0
+	Page1 Page = 1
+
+	pageUnknown = -1
+)
+
+var ErrReadOnlyRegister = errors.New("bno055: register is read-only")
+
+// Page 0 registers (see section 4.2.1 of the datasheet)
+const (
+	RegPageID = bno055PageID
+
+	RegChipID      = bno055ChipID
+	RegAccID       = bno055AccelRevID
+	RegMagID       = bno055MagRevID
+	RegGyrID       = bno055GyroRevID
+	RegSWRevIDLsb  = bno055SWRevIDLsb
+	RegSWRevIDMsb  = bno055SWRevIDMsb
+	RegBLRevID     = bno055BLRevID
+	RegAccDataX    = bno055AccelDataXLsb
+	RegMagDataX    = bno055MagDataXLsb
+	RegGyrDataX    = bno055GyroDataXLsb
+	RegEulHeading  = bno055EulerHLsb
+	RegQuaDataW    = bno055QuaternionDataWLsb
+	RegLiaDataX    = bno055LinearAccelDataXLsb
+	RegGrvDataX    = bno055GravityDataXLsb
+	RegTemp        = bno055Temp
+	RegCalibStat   = bno055CalibStat
+	RegSTResult    = bno055SelfTestResult
+	RegIntSta      = bno055IntrStat
+	RegSysClkStat  = bno055SysClkStat
+	RegSysStatus   = bno055SysStat
+	RegSysErr      = bno055SysErr
+	RegUnitSel     = bno055UnitSel
+	RegOprMode     = bno055OprMode
+	RegPwrMode     = bno055PwrMode
+	RegSysTrigger  = bno055SysTrigger
+	RegTempSource  = bno055TempSource
+	RegAxisMapConf = bno055AxisMapConfig
+	RegAxisMapSign = bno055AxisMapSign
+	RegSICMatrix   = bno055SicMatrix0Lsb
+	RegAccOffsetX  = bno055AccelOffsetXLsb
+	RegMagOffsetX  = bno055MagOffsetXLsb
+	RegGyrOffsetX  = bno055GyroOffsetXLsb
+	RegAccRadius   = bno055AccelRadiusLsb
+	RegMagRadius   = bno055MagRadiusLsb
+)
+
+// Page 1 registers (see section 4.2.2 of the datasheet)
+const (
+	RegAccConfig      = bno055AccConfig
+	RegMagConfig      = bno055MagConfig
+	RegGyrConfig0     = bno055GyroConfig0
+	RegGyrConfig1     = bno055GyroConfig1
+	RegAccSleepConfig = bno055AccSleepConfig
+	RegGyrSleepConfig = bno055GyroSleepConfig
+	RegIntMsk         = bno055IntMsk
+	RegIntEn          = bno055IntEn
+	RegAccAMThres     = bno055AccAmThres
+	RegAccIntSettings = bno055AccIntSettings
+	RegAccHGDuration  = bno055AccHgDuration
+	RegAccHGThres     = bno055AccHgThres
+	RegAccNMThres     = bno055AccNmThres
+	RegAccNMSet       = bno055AccNmSet
+	RegGyrIntSetting  = bno055GyroIntSetting
+	RegGyrHRXSet      = bno055GyroHrXSet
+	RegGyrDurX        = bno055GyroDurX
+	RegGyrHRYSet      = bno055GyroHrYSet
+	RegGyrDurY        = bno055GyroDurY
+	RegGyrHRZSet      = bno055GyroHrZSet
+	RegGyrDurZ        = bno055GyroDurZ
+	RegGyrAMThres     = bno055GyroAmThres
+	RegGyrAMSet       = bno055GyroAmSet
+	RegUniqueID       = bno055UniqueID
+)
+
+// Register bitfields
+const (
+	// UNIT_SEL
+	UnitSelAccMg              = 0x01
+	UnitSelGyrRps             = 0x02
+	UnitSelEulRad             = 0x04
+	UnitSelTempF              = 0x10
+	UnitSelOrientationAndroid = 0x80
+
+	// SYS_TRIGGER
+	SysTriggerSelfTest = 0x01
+	SysTriggerRstSys   = 0x20
+	SysTriggerRstInt   = 0x40
+	SysTriggerClkSel   = 0x80
+
+	// SYS_CLK_STATUS
+	SysClkStatusMainClk = 0x01
+
+	// ST_RESULT
+	STResultAcc = 0x01
+	STResultMag = 0x02
+	STResultGyr = 0x04
+	STResultMCU = 0x08
+
+	// CALIB_STAT (two bits each)
+	CalibStatMag = 0x03
+	CalibStatAcc = 0x0C
+	CalibStatGyr = 0x30
+	CalibStatSys = 0xC0
+
+	// INT_STA, INT_MSK and INT_EN
+	IntGyrAM       = 0x04
+	IntGyrHighRate = 0x08
+	IntAccHighG    = 0x20
+	IntAccAM       = 0x40
+	IntAccNM       = 0x80
+
+	// ACC_Config
+	AccConfigRange     = 0x03
+	AccConfigBandwidth = 0x1C
+	AccConfigPwrMode   = 0xE0
+
+	// MAG_Config
+	MagConfigDataRate = 0x07
+	MagConfigOprMode  = 0x18
+	MagConfigPwrMode  = 0x60
+
+	// GYR_Config_0
+	GyrConfig0Range     = 0x07
+	GyrConfig0Bandwidth = 0x38
+
+	// GYR_Config_1
+	GyrConfig1PwrMode = 0x07
+)
+
+func (s *Sensor) ReadRegister(page Page, reg byte) (byte, error) {
+	return s.ReadRegisterContext(context.Background(), page, reg)
+}
+
+// ReadRegisterContext reads a register, switching PAGE_ID first if needed.
+func (s *Sensor) ReadRegisterContext(ctx context.Context, page Page, reg byte) (byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readPage(ctx, page, reg)
+}
+
+func (s *Sensor) ReadRegisters(page Page, reg byte, buff []byte) error {
+	return s.ReadRegistersContext(context.Background(), page, reg, buff)
+}
+
+// ReadRegistersContext reads consecutive registers, switching PAGE_ID first if needed.
+func (s *Sensor) ReadRegistersContext(ctx context.Context, page Page, reg byte, buff []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readBufferPage(ctx, page, reg, buff)
+}
+
+func (s *Sensor) WriteRegister(page Page, reg byte, val byte) error {
+	return s.WriteRegisterContext(context.Background(), page, reg, val)
+}
+
+// WriteRegisterContext writes a register, switching PAGE_ID first if needed.
+// Registers that are only writable in CONFIG mode are written in CONFIG
+// mode, after which the previous operation mode is restored.
+//
+// Writes to OPR_MODE, PWR_MODE, UNIT_SEL, PAGE_ID and the CLK_SEL bit of
+// SYS_TRIGGER are tracked by the sensor. A reset through SYS_TRIGGER restores
+// the tracked state to the reset values, leaving the sensor in CONFIG mode on
+// page 0.
+func (s *Sensor) WriteRegisterContext(ctx context.Context, page Page, reg byte, val byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if isReadOnly(page, reg) {
+		return fmt.Errorf("%w: %s", ErrReadOnlyRegister, registerName(page, reg))
+	}
+
+	switch {
+	case reg == bno055PageID:
+		return s.selectPage(ctx, Page(val&0x01))
+	case page == Page0 && reg == bno055OprMode:
+		return s.setOperationMode(ctx, val&0x0F)
+	case page == Page0 && reg == bno055PwrMode:
+		return s.setPowerMode(ctx, val&0x03)
+	case page == Page0 && reg == bno055UnitSel:
+		return s.inConfigMode(ctx, func() error {
+			err := s.write(ctx, reg, val)
+			if err != nil {
+				return err
+			}
+
+			s.unitSel = val
+
+			return nil
+		})
+	case page == Page0 && reg == bno055SysTrigger && val&SysTriggerRstSys != 0:
+		err := s.write(ctx, reg, val)
+		if err != nil {
+			return err
+		}
+
+		// Reset values (see section 4.3 of the datasheet)
+		s.opMode = bno055OperationModeConfig
+		s.pwrMode = bno055PowerModeNormal
+		s.unitSel = UnitSelOrientationAndroid
+		s.clkSel = 0x00
+		s.page = int(Page0)
+
+		return nil
+	case page == Page0 && reg == bno055SysTrigger:
+		return s.inConfigMode(ctx, func() error {
+			err := s.write(ctx, reg, val)
+			if err != nil {
+				return err
+			}
+
+			s.clkSel = val & SysTriggerClkSel
+
+			return nil
+		})
+	}
+
+	if !isConfigRegister(page, reg) {
+		return s.writePage(ctx, page, reg, val)
+	}
+
+	return s.writeConfig(ctx, page, reg, val)
+}
+
+// Writes a register in CONFIG mode and restores the operation mode
+func (s *Sensor) writeConfig(ctx context.Context, page Page, reg byte, val byte) error {
+	return s.inConfigMode(ctx, func() error {
+		return s.writePage(ctx, page, reg, val)
+	})
+}
+
+// Runs fn in CONFIG mode and restores the operation mode. If any step fails
+// or ctx is canceled, the operation mode is restored with a background
+// context so the sensor is not left in CONFIG mode.
+func (s *Sensor) inConfigMode(ctx context.Context, fn func() error) error {
+	if s.opMode == bno055OperationModeConfig {
+		return fn()
+	}
+
+	prevMode := s.opMode
+
+	err := s.setOperationMode(ctx, bno055OperationModeConfig)
+	if err != nil {
+		return s.restoreMode(prevMode, err)
+	}
+
+	err = fn()
+	if err != nil {
+		return s.restoreMode(prevMode, err)
+	}
+
+	err = s.setOperationMode(ctx, prevMode)
+	if err != nil {
+		return s.restoreMode(prevMode, err)
+	}
+
+	return nil
+}
+
+// Restores the operation mode after err interrupted a CONFIG window
+func (s *Sensor) restoreMode(mode byte, err error) error {
+	modeErr := s.setOperationMode(context.Background(), mode)
+	if modeErr != nil {
+		return fmt.Errorf("%w (restoring operation mode failed: %v)", err, modeErr)
+	}
+
+	return err
+}
+
+func isReadOnly(page Page, reg byte) bool {
+	if page == Page1 {
+		return reg >= bno055UniqueID && reg < bno055UniqueID+16
+	}
+
+	return reg < bno055PageID || (reg > bno055PageID && reg <= bno055SysErr)
+}
+
+// Reports whether a register is only writable in CONFIG mode (see section 3.3)
+func isConfigRegister(page Page, reg byte) bool {
+	if page == Page1 {
+		return reg >= bno055AccConfig && reg <= bno055GyroAmSet && reg != bno055IntMsk && reg != bno055IntEn
+	}
+
+	return reg >= bno055UnitSel && reg <= bno055MagRadiusMsb && reg != bno055OprMode
+}
+
+func registerName(page Page, reg byte) string {
+	if page == Page0 {
+		return fmt.Sprintf("0x%02X", reg)
+	}
+
+	return fmt.Sprintf("0x%02X (page %d)", reg, page)
+}
+
+func registerRange(page Page, reg byte, n int) string {
+	if n <= 1 {
+		return registerName(page, reg)
+	}
+
+	if page == Page0 {
+		return fmt.Sprintf("0x%02X..0x%02X", reg, int(reg)+n-1)
+	}
+
+	return fmt.Sprintf("0x%02X..0x%02X (page %d)", reg, int(reg)+n-1, page)
+}
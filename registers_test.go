@@ -0,0 +1,207 @@
+package bno055_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kpeu3i/bno055"
+	"github.com/kpeu3i/bno055/bnotest"
+)
+
+func TestWriteRegisterConfigMode(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	regs := []struct {
+		page bno055.Page
+		reg  byte
+		val  byte
+	}{
+		{bno055.Page0, bno055.RegAxisMapConf, 0x21},
+		{bno055.Page0, bno055.RegTempSource, 0x00},
+		{bno055.Page1, bno055.RegAccConfig, 0x0C},
+		{bno055.Page1, bno055.RegGyrAMSet, 0x05},
+	}
+
+	for _, reg := range regs {
+		err := sensor.WriteRegister(reg.page, reg.reg, reg.val)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if val := device.Register(int(reg.page), reg.reg); val != reg.val {
+			t.Errorf("page %d register 0x%02X = 0x%02X, want 0x%02X", reg.page, reg.reg, val, reg.val)
+		}
+
+		if mode := device.OperationMode(); mode != byte(bno055.OperationModeNDOF) {
+			t.Fatalf("device left in operation mode 0x%02X, want NDOF", mode)
+		}
+	}
+}
+
+func TestWriteRegisterAnyMode(t *testing.T) {
+	var modeSwitches int
+
+	device := bnotest.NewDevice()
+	bus := &hookBus{
+		Device: device,
+		hook: func(page int, reg byte, buff []byte) error {
+			if page == bnotest.Page0 && reg == bno055.RegOprMode {
+				modeSwitches++
+			}
+
+			return nil
+		},
+	}
+
+	sensor, err := bno055.NewSensorFromBus(bus, bno055.WithoutReset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modeSwitches = 0
+
+	err = sensor.WriteRegister(bno055.Page1, bno055.RegIntMsk, 0x44)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sensor.WriteRegister(bno055.Page1, bno055.RegIntEn, 0x44)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if modeSwitches != 0 {
+		t.Fatalf("%d operation mode switches for INT_MSK and INT_EN, want none", modeSwitches)
+	}
+
+	if val := device.Register(bnotest.Page1, bno055.RegIntEn); val != 0x44 {
+		t.Fatalf("INT_EN = 0x%02X, want 0x44", val)
+	}
+}
+
+func TestWriteRegisterReadOnly(t *testing.T) {
+	sensor, _ := newTestSensor(t)
+
+	err := sensor.WriteRegister(bno055.Page0, bno055.RegChipID, 0x00)
+	if !errors.Is(err, bno055.ErrReadOnlyRegister) {
+		t.Fatalf("WriteRegister error = %v, want %v", err, bno055.ErrReadOnlyRegister)
+	}
+}
+
+func TestWriteRegisterTracksState(t *testing.T) {
+	sensor, _ := newTestSensor(t)
+
+	err := sensor.WriteRegister(bno055.Page0, bno055.RegOprMode, byte(bno055.OperationModeAMG))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode := sensor.OperationMode(); mode != bno055.OperationModeAMG {
+		t.Fatalf("OperationMode = %s, want AMG", mode)
+	}
+
+	err = sensor.WriteRegister(bno055.Page0, bno055.RegUnitSel, bno055.UnitSelAccMg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if units := sensor.Units(); units.Acceleration != bno055.UnitMilliG {
+		t.Fatalf("acceleration unit = %s, want %s", units.Acceleration, bno055.UnitMilliG)
+	}
+
+	err = sensor.WriteRegister(bno055.Page0, bno055.RegPwrMode, byte(bno055.PowerModeSuspend))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = sensor.Accelerometer()
+	if !errors.Is(err, bno055.ErrSuspended) {
+		t.Fatalf("Accelerometer error = %v, want %v", err, bno055.ErrSuspended)
+	}
+}
+
+func TestWriteRegisterReset(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	err := sensor.SetUnits(bno055.Units{Angle: bno055.UnitRadians})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Leave page 1 selected
+	_, err = sensor.ReadRegister(bno055.Page1, bno055.RegAccConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sensor.WriteRegister(bno055.Page0, bno055.RegSysTrigger, bno055.SysTriggerRstSys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if device.Resets() != 1 {
+		t.Fatalf("device reset %d times, want 1", device.Resets())
+	}
+
+	if mode := sensor.OperationMode(); mode != bno055.OperationModeConfig {
+		t.Fatalf("OperationMode = %s after reset, want CONFIG", mode)
+	}
+
+	if mode := sensor.PowerMode(); mode != bno055.PowerModeNormal {
+		t.Fatalf("PowerMode = %s after reset, want normal", mode)
+	}
+
+	if units := sensor.Units(); units.Angle != bno055.UnitDegrees || units.Orientation != bno055.OrientationAndroid {
+		t.Fatalf("Units = %+v after reset, want the reset values", units)
+	}
+
+	// The reset selects page 0 without a PAGE_ID write
+	val, err := sensor.ReadRegister(bno055.Page0, bno055.RegUnitSel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val != bno055.UnitSelOrientationAndroid {
+		t.Fatalf("UNIT_SEL = 0x%02X after reset, want 0x%02X", val, bno055.UnitSelOrientationAndroid)
+	}
+}
+
+func TestWriteRegisterTracksClockSelection(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	err := sensor.WriteRegister(bno055.Page0, bno055.RegSysTrigger, bno055.SysTriggerClkSel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegSysTrigger); val != bno055.SysTriggerClkSel {
+		t.Fatalf("SYS_TRIGGER = 0x%02X, want 0x%02X", val, bno055.SysTriggerClkSel)
+	}
+
+	// The simulator only stores CLK_SEL in CONFIG mode, so clear the
+	// interrupts there to see the clock selection written along with RST_INT
+	err = sensor.WriteRegister(bno055.Page0, bno055.RegOprMode, byte(bno055.OperationModeConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sensor.ResetInterrupts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegSysTrigger); val != bno055.SysTriggerClkSel {
+		t.Fatalf("SYS_TRIGGER = 0x%02X after RST_INT, want 0x%02X", val, bno055.SysTriggerClkSel)
+	}
+
+	externalCrystal := false
+
+	err = sensor.Apply(&bno055.Config{ExternalCrystal: &externalCrystal})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegSysTrigger); val != 0x00 {
+		t.Fatalf("SYS_TRIGGER = 0x%02X after selecting the internal oscillator, want 0x00", val)
+	}
+}
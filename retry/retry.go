@@ -0,0 +1,173 @@
+// Package retry provides retry policies for bus transports.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Policy decides how a failed bus operation is retried.
+type Policy interface {
+	// Do calls fn until it succeeds or the policy gives up, and returns
+	// the last error. It stops early with the context error once ctx is done.
+	Do(ctx context.Context, fn func() error) error
+}
+
+// Classifier reports whether err is transient and worth retrying.
+type Classifier func(err error) bool
+
+// Attempt describes a single call made by a policy.
+type Attempt struct {
+	// Number of the attempt, starting at 1
+	Number int
+	// Error returned by the attempt, nil on success
+	Err error
+	// Time elapsed since the first attempt started
+	Elapsed time.Duration
+	// Delay before the next attempt, zero if there is none
+	Delay time.Duration
+	// Whether another attempt follows
+	Retry bool
+}
+
+// Backoff retries transient errors with exponentially growing delays.
+type Backoff struct {
+	// Maximum number of attempts, including the first one.
+	// Zero or less means no limit other than MaxElapsedTime and the context.
+	MaxAttempts int
+	// Delay before the first retry
+	InitialDelay time.Duration
+	// Upper bound for the delay, zero means no bound
+	MaxDelay time.Duration
+	// Factor the delay is multiplied by after each retry, values below 1 are treated as 1
+	Multiplier float64
+	// Randomization factor (0..1): each delay is picked from [d-d*Jitter, d+d*Jitter]
+	Jitter float64
+	// No retry is started once it would end after this much time since
+	// the first attempt, zero means no limit
+	MaxElapsedTime time.Duration
+	// Decides which errors are retried, IsTransient is used if nil
+	Classify Classifier
+	// Called after every attempt, if set
+	Observer func(attempt Attempt)
+}
+
+// Fixed returns a policy that retries up to retryCount times with a
+// constant delay, which is the behavior of bno055.WithRetry.
+func Fixed(retryCount int, delay time.Duration) *Backoff {
+	backoff := &Backoff{
+		MaxAttempts:  retryCount + 1,
+		InitialDelay: delay,
+		Multiplier:   1,
+	}
+
+	return backoff
+}
+
+func (b *Backoff) Do(ctx context.Context, fn func() error) error {
+	classify := b.Classify
+	if classify == nil {
+		classify = IsTransient
+	}
+
+	start := time.Now()
+	delay := b.InitialDelay
+
+	for i := 1; ; i++ {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		err = fn()
+
+		attempt := Attempt{
+			Number:  i,
+			Err:     err,
+			Elapsed: time.Since(start),
+		}
+
+		if err != nil && classify(err) && (b.MaxAttempts <= 0 || i < b.MaxAttempts) {
+			attempt.Delay = b.jitter(delay)
+			attempt.Retry = b.MaxElapsedTime <= 0 || attempt.Elapsed+attempt.Delay <= b.MaxElapsedTime
+		}
+
+		if !attempt.Retry {
+			attempt.Delay = 0
+		}
+
+		if b.Observer != nil {
+			b.Observer(attempt)
+		}
+
+		if !attempt.Retry {
+			return err
+		}
+
+		timer := time.NewTimer(attempt.Delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		delay = b.next(delay)
+	}
+}
+
+func (b *Backoff) next(delay time.Duration) time.Duration {
+	if b.Multiplier > 1 {
+		delay = time.Duration(float64(delay) * b.Multiplier)
+	}
+
+	if b.MaxDelay > 0 && delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+
+	return delay
+}
+
+func (b *Backoff) jitter(delay time.Duration) time.Duration {
+	if b.Jitter <= 0 || delay <= 0 {
+		return delay
+	}
+
+	delta := b.Jitter * float64(delay)
+
+	return time.Duration(float64(delay) - delta + rand.Float64()*2*delta)
+}
+
+// IsTransient is the default classifier. It gives up at once on errors
+// that cannot go away by themselves, such as a missing device, a closed or
+// bad file descriptor, or a done context. Other errno values (EREMOTEIO,
+// ETIMEDOUT, EAGAIN...) are retried. Other errors with a Temporary method
+// are classified by it, and everything else is retried.
+func IsTransient(err error) bool {
+	if errors.Is(err, os.ErrClosed) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		switch errno {
+		case syscall.ENODEV, syscall.ENOENT, syscall.EBADF, syscall.EINVAL, syscall.EPERM, syscall.EACCES, syscall.ENOTTY, syscall.EOPNOTSUPP:
+			return false
+		}
+
+		// EREMOTEIO, ETIMEDOUT, EAGAIN and other bus errors
+		return true
+	}
+
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) {
+		return temporary.Temporary()
+	}
+
+	return true
+}
@@ -2,12 +2,15 @@
 package bno055
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/kpeu3i/bno055/i2c"
+	"github.com/kpeu3i/bno055/retry"
 )
 
 type Status struct {
@@ -59,9 +62,10 @@ type Revision struct {
 }
 
 type Vector struct {
-	X float32
-	Y float32
-	Z float32
+	X    float32
+	Y    float32
+	Z    float32
+	Unit Unit
 }
 
 type Quaternion struct {
@@ -71,11 +75,6 @@ type Quaternion struct {
 	W float32
 }
 
-var defaultCalibrationOffsets CalibrationOffsets = []byte{
-	239, 255, 184, 255, 10, 0, 196, 0, 193, 0,
-	85, 255, 128, 0, 0, 0, 1, 0, 232, 3, 0, 0,
-}
-
 type I2CBus interface {
 	Read(reg byte) (byte, error)
 	Write(reg byte, val byte) error
@@ -87,56 +86,87 @@ type I2CBus interface {
 type Option func(config *config)
 
 type config struct {
-	retryCount   int
-	retryTimeout time.Duration
+	retryCount        int
+	retryTimeout      time.Duration
+	splitTransactions bool
+	smbus             bool
+	retryPolicy       retry.Policy
+	chunkSize         int
+	chunkDelay        time.Duration
+
+	skipReset          bool
+	skipInit           bool
+	externalCrystal    bool
+	calibrationOffsets CalibrationOffsets
+	operationMode      *OperationMode
+	powerMode          *PowerMode
+	temperatureSource  *TemperatureSource
 }
 
-type Sensor struct {
-	mu     sync.Mutex
-	bus    I2CBus
-	opMode byte
-}
-
-func (s *Sensor) Status() (*Status, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	err := s.bus.Write(bno055PageID, 0x0)
-	if err != nil {
-		return nil, err
+func (c *config) busOptions() []i2c.Option {
+	var busOptions []i2c.Option
+	if c.splitTransactions {
+		busOptions = append(busOptions, i2c.WithSplitTransactions())
 	}
 
-	prevMode := s.opMode
+	if c.smbus {
+		busOptions = append(busOptions, i2c.WithSMBus())
+	}
 
-	err = s.setOperationMode(bno055OperationModeConfig)
-	if err != nil {
-		return nil, err
+	if c.retryPolicy != nil {
+		busOptions = append(busOptions, i2c.WithRetryPolicy(c.retryPolicy))
 	}
 
-	sysTrigger, err := s.bus.Read(bno055SysTrigger)
-	err = s.bus.Write(bno055SysTrigger, sysTrigger|0x1)
-	if err != nil {
-		return nil, err
+	if c.chunkSize > 0 {
+		busOptions = append(busOptions, i2c.WithChunking(c.chunkSize, c.chunkDelay))
 	}
 
-	time.Sleep(time.Second)
+	return busOptions
+}
 
-	err = s.setOperationMode(prevMode)
+type Sensor struct {
+	mu      sync.Mutex
+	bus     I2CBus
+	opMode  byte
+	pwrMode byte
+	unitSel byte
+	clkSel  byte
+	page    int
+	edges   EdgeSource
+}
+
+func (s *Sensor) Status() (*Status, error) {
+	return s.StatusContext(context.Background())
+}
+
+func (s *Sensor) StatusContext(ctx context.Context) (*Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.inConfigMode(ctx, func() error {
+		// SYS_TRIGGER is write-only, so the clock selection is written from the cached value
+		err := s.write(ctx, bno055SysTrigger, s.clkSel|SysTriggerSelfTest)
+		if err != nil {
+			return err
+		}
+
+		return s.sleep(ctx, time.Second, "self test delay")
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	system, err := s.bus.Read(bno055SysStat)
+	system, err := s.read(ctx, bno055SysStat)
 	if err != nil {
 		return nil, err
 	}
 
-	selfTest, err := s.bus.Read(bno055SelfTestResult)
+	selfTest, err := s.read(ctx, bno055SelfTestResult)
 	if err != nil {
 		return nil, err
 	}
 
-	systemError, err := s.bus.Read(bno055SysErr)
+	systemError, err := s.read(ctx, bno055SysErr)
 	if err != nil {
 		return nil, err
 	}
@@ -151,35 +181,39 @@ func (s *Sensor) Status() (*Status, error) {
 }
 
 func (s *Sensor) Revision() (*Revision, error) {
+	return s.RevisionContext(context.Background())
+}
+
+func (s *Sensor) RevisionContext(ctx context.Context) (*Revision, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	accelerometer, err := s.bus.Read(bno055AccelRevID)
+	accelerometer, err := s.read(ctx, bno055AccelRevID)
 	if err != nil {
 		return nil, err
 	}
 
-	magnetometer, err := s.bus.Read(bno055MagRevID)
+	magnetometer, err := s.read(ctx, bno055MagRevID)
 	if err != nil {
 		return nil, err
 	}
 
-	gyroscope, err := s.bus.Read(bno055GyroRevID)
+	gyroscope, err := s.read(ctx, bno055GyroRevID)
 	if err != nil {
 		return nil, err
 	}
 
-	bootloader, err := s.bus.Read(bno055BLRevID)
+	bootloader, err := s.read(ctx, bno055BLRevID)
 	if err != nil {
 		return nil, err
 	}
 
-	swLSB, err := s.bus.Read(bno055SWRevIDLsb)
+	swLSB, err := s.read(ctx, bno055SWRevIDLsb)
 	if err != nil {
 		return nil, err
 	}
 
-	swMSB, err := s.bus.Read(bno055SWRevIDMsb)
+	swMSB, err := s.read(ctx, bno055SWRevIDMsb)
 	if err != nil {
 		return nil, err
 	}
@@ -198,64 +232,51 @@ func (s *Sensor) Revision() (*Revision, error) {
 }
 
 func (s *Sensor) UseExternalCrystal(b bool) error {
+	return s.UseExternalCrystalContext(context.Background(), b)
+}
+
+func (s *Sensor) UseExternalCrystalContext(ctx context.Context, b bool) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	prevMode := s.opMode
-
-	err := s.setOperationMode(bno055OperationModeConfig)
-	if err != nil {
-		return err
-	}
-
-	err = s.bus.Write(bno055PageID, 0)
-	if err != nil {
-		return err
-	}
-
-	if b {
-		err = s.bus.Write(bno055SysTrigger, 0x80)
-		if err != nil {
-			return err
-		}
-	} else {
-		err = s.bus.Write(bno055SysTrigger, 0x00)
-		if err != nil {
-			return err
+	return s.inConfigMode(ctx, func() error {
+		if b {
+			err := s.write(ctx, bno055SysTrigger, 0x80)
+			if err != nil {
+				return err
+			}
+
+			s.clkSel = 0x80
+		} else {
+			err := s.write(ctx, bno055SysTrigger, 0x00)
+			if err != nil {
+				return err
+			}
+
+			s.clkSel = 0x00
 		}
-	}
-
-	err = s.setOperationMode(prevMode)
-	if err != nil {
-		return err
-	}
 
-	return nil
+		return nil
+	})
 }
 
 func (s *Sensor) Calibration() (CalibrationOffsets, *CalibrationStatus, error) {
+	return s.CalibrationContext(context.Background())
+}
+
+func (s *Sensor) CalibrationContext(ctx context.Context) (CalibrationOffsets, *CalibrationStatus, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	status, err := s.bus.Read(bno055CalibStat)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	prevMode := s.opMode
-
-	err = s.setOperationMode(bno055OperationModeConfig)
+	status, err := s.read(ctx, bno055CalibStat)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	offsets := make([]byte, 22)
-	err = s.bus.ReadBuffer(bno055AccelOffsetXLsb, offsets)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	err = s.setOperationMode(prevMode)
+	err = s.inConfigMode(ctx, func() error {
+		return s.readBuffer(ctx, bno055AccelOffsetXLsb, offsets)
+	})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -267,39 +288,32 @@ func (s *Sensor) Calibration() (CalibrationOffsets, *CalibrationStatus, error) {
 }
 
 func (s *Sensor) Calibrate(offsets CalibrationOffsets) error {
+	return s.CalibrateContext(context.Background(), offsets)
+}
+
+func (s *Sensor) CalibrateContext(ctx context.Context, offsets CalibrationOffsets) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	prevMode := s.opMode
-
-	err := s.setOperationMode(bno055OperationModeConfig)
-	if err != nil {
-		return err
-	}
-
-	err = s.bus.WriteBuffer(bno055AccelOffsetXLsb, offsets)
-	if err != nil {
-		return err
-	}
-
-	err = s.setOperationMode(prevMode)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return s.inConfigMode(ctx, func() error {
+		return s.writeBuffer(ctx, bno055AccelOffsetXLsb, offsets)
+	})
 }
 
 func (s *Sensor) AxisConfig() (*AxisConfig, error) {
+	return s.AxisConfigContext(context.Background())
+}
+
+func (s *Sensor) AxisConfigContext(ctx context.Context) (*AxisConfig, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	mapConfig, err := s.bus.Read(bno055AxisMapConfig)
+	mapConfig, err := s.read(ctx, bno055AxisMapConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	signConfig, err := s.bus.Read(bno055AxisMapSign)
+	signConfig, err := s.read(ctx, bno055AxisMapSign)
 	if err != nil {
 		return nil, err
 	}
@@ -323,162 +337,260 @@ func (s *Sensor) AxisConfig() (*AxisConfig, error) {
 //          |____________|/
 //
 func (s *Sensor) RemapAxis(config *AxisConfig) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	prevMode := s.opMode
+	return s.RemapAxisContext(context.Background(), config)
+}
 
-	err := s.setOperationMode(bno055OperationModeConfig)
+func (s *Sensor) RemapAxisContext(ctx context.Context, config *AxisConfig) error {
+	err := config.Validate()
 	if err != nil {
 		return err
 	}
 
-	err = s.bus.Write(bno055AxisMapConfig, config.Mappings())
-	if err != nil {
-		return err
-	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	err = s.bus.Write(bno055AxisMapSign, config.Signs())
-	if err != nil {
-		return err
-	}
+	return s.inConfigMode(ctx, func() error {
+		err := s.write(ctx, bno055AxisMapConfig, config.Mappings())
+		if err != nil {
+			return err
+		}
 
-	err = s.setOperationMode(prevMode)
-	if err != nil {
-		return err
-	}
+		return s.write(ctx, bno055AxisMapSign, config.Signs())
+	})
+}
 
-	return nil
+func (s *Sensor) Temperature() (*Temperature, error) {
+	return s.TemperatureContext(context.Background())
 }
 
-func (s *Sensor) Temperature() (int8, error) {
+func (s *Sensor) TemperatureContext(ctx context.Context) (*Temperature, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	temperature, err := s.bus.Read(bno055Temp)
+	err := s.checkAwake()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := s.read(ctx, bno055Temp)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	units := newUnits(s.unitSel)
+
+	// 1C = 1 LSB, 2F = 1 LSB
+	temperature := &Temperature{
+		Value: float32(int8(value)) / units.temperatureScale(),
+		Unit:  units.Temperature,
 	}
 
-	return int8(temperature), nil
+	return temperature, nil
 }
 
 func (s *Sensor) Magnetometer() (*Vector, error) {
+	return s.MagnetometerContext(context.Background())
+}
+
+func (s *Sensor) MagnetometerContext(ctx context.Context) (*Vector, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	x, y, z, err := s.readVector(bno055MagDataXLsb)
+	err := s.checkOutput(OutputMagnetometer)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y, z, err := s.readVector(ctx, bno055MagDataXLsb)
 	if err != nil {
 		return nil, err
 	}
 
 	// 1uT = 16 LSB
 	vector := &Vector{
-		X: float32(x) / 16,
-		Y: float32(y) / 16,
-		Z: float32(z) / 16,
+		X:    float32(x) / 16,
+		Y:    float32(y) / 16,
+		Z:    float32(z) / 16,
+		Unit: UnitMicroTesla,
 	}
 
 	return vector, nil
 }
 
 func (s *Sensor) Gyroscope() (*Vector, error) {
+	return s.GyroscopeContext(context.Background())
+}
+
+func (s *Sensor) GyroscopeContext(ctx context.Context) (*Vector, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	x, y, z, err := s.readVector(bno055GyroDataXLsb)
+	err := s.checkOutput(OutputGyroscope)
 	if err != nil {
 		return nil, err
 	}
 
-	// 1 degree = 16 LSB
+	x, y, z, err := s.readVector(ctx, bno055GyroDataXLsb)
+	if err != nil {
+		return nil, err
+	}
+
+	units := newUnits(s.unitSel)
+	scale := units.angularRateScale()
+
+	// 1dps = 16 LSB, 1rps = 900 LSB
 	vector := &Vector{
-		X: float32(x) / 16,
-		Y: float32(y) / 16,
-		Z: float32(z) / 16,
+		X:    float32(x) / scale,
+		Y:    float32(y) / scale,
+		Z:    float32(z) / scale,
+		Unit: units.AngularRate,
 	}
 
 	return vector, nil
 }
 
 func (s *Sensor) Euler() (*Vector, error) {
+	return s.EulerContext(context.Background())
+}
+
+func (s *Sensor) EulerContext(ctx context.Context) (*Vector, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	x, y, z, err := s.readVector(bno055EulerHLsb)
+	err := s.checkOutput(OutputEuler)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y, z, err := s.readVector(ctx, bno055EulerHLsb)
 	if err != nil {
 		return nil, err
 	}
 
-	// 1rps = 16 LSB
+	units := newUnits(s.unitSel)
+	scale := units.angleScale()
+
+	// 1 degree = 16 LSB, 1 radian = 900 LSB
 	vector := &Vector{
-		X: float32(x) / 16,
-		Y: float32(y) / 16,
-		Z: float32(z) / 16,
+		X:    float32(x) / scale,
+		Y:    float32(y) / scale,
+		Z:    float32(z) / scale,
+		Unit: units.Angle,
 	}
 
 	return vector, nil
 }
 
 func (s *Sensor) Accelerometer() (*Vector, error) {
+	return s.AccelerometerContext(context.Background())
+}
+
+func (s *Sensor) AccelerometerContext(ctx context.Context) (*Vector, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	x, y, z, err := s.readVector(bno055AccelDataXLsb)
+	err := s.checkOutput(OutputAccelerometer)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y, z, err := s.readVector(ctx, bno055AccelDataXLsb)
 	if err != nil {
 		return nil, err
 	}
 
-	// 1m/s^2 = 100 LSB
+	units := newUnits(s.unitSel)
+	scale := units.accelerationScale()
+
+	// 1m/s^2 = 100 LSB, 1mg = 1 LSB
 	vector := &Vector{
-		X: float32(x) / 100,
-		Y: float32(y) / 100,
-		Z: float32(z) / 100,
+		X:    float32(x) / scale,
+		Y:    float32(y) / scale,
+		Z:    float32(z) / scale,
+		Unit: units.Acceleration,
 	}
 
 	return vector, nil
 }
 
 func (s *Sensor) LinearAccelerometer() (*Vector, error) {
+	return s.LinearAccelerometerContext(context.Background())
+}
+
+func (s *Sensor) LinearAccelerometerContext(ctx context.Context) (*Vector, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	x, y, z, err := s.readVector(bno055LinearAccelDataXLsb)
+	err := s.checkOutput(OutputLinearAcceleration)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y, z, err := s.readVector(ctx, bno055LinearAccelDataXLsb)
 	if err != nil {
 		return nil, err
 	}
 
-	// 1m/s^2 = 100 LSB
+	units := newUnits(s.unitSel)
+	scale := units.accelerationScale()
+
+	// 1m/s^2 = 100 LSB, 1mg = 1 LSB
 	vector := &Vector{
-		X: float32(x) / 100,
-		Y: float32(y) / 100,
-		Z: float32(z) / 100,
+		X:    float32(x) / scale,
+		Y:    float32(y) / scale,
+		Z:    float32(z) / scale,
+		Unit: units.Acceleration,
 	}
 
 	return vector, nil
 }
 
 func (s *Sensor) Gravity() (*Vector, error) {
+	return s.GravityContext(context.Background())
+}
+
+func (s *Sensor) GravityContext(ctx context.Context) (*Vector, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	x, y, z, err := s.readVector(bno055GravityDataXLsb)
+	err := s.checkOutput(OutputGravity)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y, z, err := s.readVector(ctx, bno055GravityDataXLsb)
 	if err != nil {
 		return nil, err
 	}
 
-	// 1m/s^2 = 100 LSB
+	units := newUnits(s.unitSel)
+	scale := units.accelerationScale()
+
+	// 1m/s^2 = 100 LSB, 1mg = 1 LSB
 	vector := &Vector{
-		X: float32(x) / 100,
-		Y: float32(y) / 100,
-		Z: float32(z) / 100,
+		X:    float32(x) / scale,
+		Y:    float32(y) / scale,
+		Z:    float32(z) / scale,
+		Unit: units.Acceleration,
 	}
 
 	return vector, nil
 }
 
 func (s *Sensor) Quaternion() (*Quaternion, error) {
-	w, x, y, z, err := s.readQuaternion(bno055QuaternionDataWLsb)
+	return s.QuaternionContext(context.Background())
+}
+
+func (s *Sensor) QuaternionContext(ctx context.Context) (*Quaternion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.checkOutput(OutputQuaternion)
+	if err != nil {
+		return nil, err
+	}
+
+	w, x, y, z, err := s.readQuaternion(ctx, bno055QuaternionDataWLsb)
 	if err != nil {
 		return nil, err
 	}
@@ -496,74 +608,83 @@ func (s *Sensor) Quaternion() (*Quaternion, error) {
 }
 
 func (s *Sensor) Sleep() error {
+	return s.SleepContext(context.Background())
+}
+
+func (s *Sensor) SleepContext(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	prevMode := s.opMode
-
-	err := s.setOperationMode(bno055OperationModeConfig)
-	if err != nil {
-		return err
-	}
+	return s.setPowerMode(ctx, bno055PowerModeSuspend)
+}
 
-	err = s.bus.Write(bno055PwrMode, bno055PowerModeSuspend)
-	if err != nil {
-		return err
-	}
+func (s *Sensor) Wakeup() error {
+	return s.WakeupContext(context.Background())
+}
 
-	err = s.setOperationMode(prevMode)
-	if err != nil {
-		return err
-	}
+func (s *Sensor) WakeupContext(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return nil
+	return s.setPowerMode(ctx, bno055PowerModeNormal)
 }
 
-func (s *Sensor) Wakeup() error {
+func (s *Sensor) Close() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	prevMode := s.opMode
+	return s.bus.Close()
+}
 
-	err := s.setOperationMode(bno055OperationModeConfig)
-	if err != nil {
-		return err
+// Switches the operation mode, going through CONFIG mode when switching
+// between two other modes (see section 3.3 of the datasheet)
+func (s *Sensor) setOperationMode(ctx context.Context, mode byte) error {
+	if mode != bno055OperationModeConfig && s.opMode != bno055OperationModeConfig {
+		err := s.switchOperationMode(ctx, bno055OperationModeConfig)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = s.bus.Write(bno055PwrMode, bno055PowerModeNormal)
-	if err != nil {
-		return err
-	}
+	return s.switchOperationMode(ctx, mode)
+}
 
-	err = s.setOperationMode(prevMode)
+// Writes OPR_MODE, waits for the switching time in table 3-6 of the
+// datasheet and reads the mode back
+func (s *Sensor) switchOperationMode(ctx context.Context, mode byte) error {
+	err := s.write(ctx, bno055OprMode, mode)
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	s.opMode = mode
 
-func (s *Sensor) Close() error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	delay := 7 * time.Millisecond
+	if mode == bno055OperationModeConfig {
+		delay = 19 * time.Millisecond
+	}
 
-	return s.bus.Close()
-}
+	err = s.sleep(ctx, delay, "operation mode switch delay")
+	if err != nil {
+		return err
+	}
 
-func (s *Sensor) setOperationMode(mode byte) error {
-	err := s.bus.Write(bno055OprMode, mode)
+	opMode, err := s.read(ctx, bno055OprMode)
 	if err != nil {
 		return err
 	}
 
-	s.opMode = mode
+	if opMode&0x0F != mode {
+		s.opMode = opMode & 0x0F
+		return fmt.Errorf("%w: operation mode 0x%02X, want 0x%02X", ErrVerifyFailed, opMode&0x0F, mode)
+	}
 
 	return nil
 }
 
-func (s *Sensor) readVector(addr byte) (x, y, z int16, err error) {
+func (s *Sensor) readVector(ctx context.Context, addr byte) (x, y, z int16, err error) {
 	buf := make([]byte, 6)
-	err = s.bus.ReadBuffer(addr, buf)
+	err = s.readBuffer(ctx, addr, buf)
 	if err != nil {
 		return
 	}
@@ -575,9 +696,9 @@ func (s *Sensor) readVector(addr byte) (x, y, z int16, err error) {
 	return
 }
 
-func (s *Sensor) readQuaternion(addr byte) (w, x, y, z int16, err error) {
+func (s *Sensor) readQuaternion(ctx context.Context, addr byte) (w, x, y, z int16, err error) {
 	buf := make([]byte, 8)
-	err = s.bus.ReadBuffer(addr, buf)
+	err = s.readBuffer(ctx, addr, buf)
 	if err != nil {
 		return
 	}
@@ -590,9 +711,9 @@ func (s *Sensor) readQuaternion(addr byte) (w, x, y, z int16, err error) {
 	return
 }
 
-func (s *Sensor) checkExists() error {
+func (s *Sensor) checkExists(ctx context.Context) error {
 	for i := 0; i < 10; i++ {
-		id, err := s.bus.Read(bno055ChipID)
+		id, err := s.read(ctx, bno055ChipID)
 		if err != nil {
 			return err
 		}
@@ -601,76 +722,173 @@ func (s *Sensor) checkExists() error {
 			return nil
 		}
 
-		time.Sleep(100 * time.Millisecond)
+		err = s.sleep(ctx, 100*time.Millisecond, "chip id retry delay")
+		if err != nil {
+			return err
+		}
 	}
 
 	return errors.New("sensor not found")
 }
 
-func (s *Sensor) init() error {
-	err := s.checkExists()
+func (s *Sensor) init(ctx context.Context, config *config) error {
+	if config.skipInit {
+		return s.attach(ctx, config)
+	}
+
+	operationMode := OperationModeNDOF
+	if config.operationMode != nil {
+		operationMode = *config.operationMode
+	}
+
+	powerMode := PowerModeNormal
+	if config.powerMode != nil {
+		powerMode = *config.powerMode
+	}
+
+	// Gyroscope by default, as it seems to be more accurate
+	temperatureSource := TemperatureSourceGyroscope
+	if config.temperatureSource != nil {
+		temperatureSource = *config.temperatureSource
+	}
+
+	if !operationMode.IsValid() {
+		return fmt.Errorf("bno055: invalid operation mode 0x%02X", byte(operationMode))
+	}
+
+	if !powerMode.IsValid() {
+		return fmt.Errorf("bno055: invalid power mode 0x%02X", byte(powerMode))
+	}
+
+	if temperatureSource > TemperatureSourceGyroscope {
+		return fmt.Errorf("bno055: invalid temperature source 0x%02X", byte(temperatureSource))
+	}
+
+	if config.calibrationOffsets != nil && len(config.calibrationOffsets) != 22 {
+		return fmt.Errorf("bno055: calibration offsets must be 22 bytes, got %d", len(config.calibrationOffsets))
+	}
+
+	err := s.checkExists(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = s.setOperationMode(bno055OperationModeConfig)
+	err = s.setOperationMode(ctx, bno055OperationModeConfig)
+	if err != nil {
+		return err
+	}
+
+	if !config.skipReset {
+		// Reset the device using the reset command
+		err = s.write(ctx, bno055SysTrigger, 0x20)
+		if err != nil {
+			return err
+		}
+
+		// The reset selects page 0
+		s.page = int(Page0)
+
+		err = s.sleep(ctx, 1000*time.Millisecond, "reset delay")
+		if err != nil {
+			return err
+		}
+
+		err = s.checkExists(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.write(ctx, bno055PwrMode, byte(powerMode))
 	if err != nil {
 		return err
 	}
 
-	// Reset the device using the reset command
-	err = s.bus.Write(bno055SysTrigger, 0x20)
+	s.pwrMode = byte(powerMode)
+
+	// Default to internal oscillator
+	clkSel := byte(0x00)
+	if config.externalCrystal {
+		clkSel = SysTriggerClkSel
+	}
+
+	err = s.write(ctx, bno055SysTrigger, clkSel)
 	if err != nil {
 		return err
 	}
 
-	time.Sleep(1000 * time.Millisecond)
+	s.clkSel = clkSel
 
-	err = s.checkExists()
+	err = s.write(ctx, bno055TempSource, byte(temperatureSource))
 	if err != nil {
 		return err
 	}
 
-	// Set to normal power mode
-	err = s.bus.Write(bno055PwrMode, bno055PowerModeNormal)
+	// Set the unit selection bits
+	err = s.write(ctx, bno055UnitSel, 0x0)
 	if err != nil {
 		return err
 	}
 
-	err = s.bus.Write(bno055PageID, 0x0)
+	s.unitSel = 0x0
+
+	if config.calibrationOffsets != nil {
+		err = s.writeBuffer(ctx, bno055AccelOffsetXLsb, config.calibrationOffsets)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.setOperationMode(ctx, byte(operationMode))
 	if err != nil {
 		return err
 	}
 
-	// Default to internal oscillator
-	err = s.bus.Write(bno055SysTrigger, 0x00)
+	return nil
+}
+
+// Reads the state of a configured sensor. Only PAGE_ID is written, and it
+// is restored afterwards.
+func (s *Sensor) attach(ctx context.Context, config *config) error {
+	page, err := s.readBus(ctx, "read of register "+registerName(Page0, bno055PageID), bno055PageID)
 	if err != nil {
 		return err
 	}
 
-	// Set temperature source to gyroscope, as it seems to be more accurate
-	err = s.bus.Write(bno055TempSource, 0x01)
+	s.page = int(page & 0x01)
+	prevPage := Page(s.page)
+
+	err = s.checkExists(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Set the unit selection bits
-	err = s.bus.Write(bno055UnitSel, 0x0)
+	opMode, err := s.read(ctx, bno055OprMode)
 	if err != nil {
 		return err
 	}
 
-	err = s.setOperationMode(bno055OperationModeNdof)
+	pwrMode, err := s.read(ctx, bno055PwrMode)
 	if err != nil {
 		return err
 	}
 
-	err = s.Calibrate(defaultCalibrationOffsets)
+	unitSel, err := s.read(ctx, bno055UnitSel)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	s.opMode = opMode & 0x0F
+	s.pwrMode = pwrMode & 0x03
+	s.unitSel = unitSel
+
+	// SYS_TRIGGER is write-only, so the clock selection comes from the options
+	s.clkSel = 0x00
+	if config.externalCrystal {
+		s.clkSel = SysTriggerClkSel
+	}
+
+	return s.selectPage(ctx, prevPage)
 }
 
 func WithRetry(retryCount int, retryTimeout time.Duration) Option {
@@ -680,37 +898,147 @@ func WithRetry(retryCount int, retryTimeout time.Duration) Option {
 	}
 }
 
+// WithSplitTransactions makes register reads use a separate write and read
+// instead of a single I2C_RDWR transaction (see i2c.WithSplitTransactions).
+func WithSplitTransactions() Option {
+	return func(config *config) {
+		config.splitTransactions = true
+	}
+}
+
+// WithSMBus forces I2C_SMBUS transfers (see i2c.WithSMBus). They are
+// picked automatically for SMBus-only adapters.
+func WithSMBus() Option {
+	return func(config *config) {
+		config.smbus = true
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed bus operations.
+// It takes precedence over WithRetry.
+func WithRetryPolicy(policy retry.Policy) Option {
+	return func(config *config) {
+		config.retryPolicy = policy
+	}
+}
+
+// WithoutReset skips the reset of the sensor and the delay it needs.
+// The remaining initialization steps still run.
+func WithoutReset() Option {
+	return func(config *config) {
+		config.skipReset = true
+	}
+}
+
+// WithoutInit attaches to an already configured sensor without changing
+// its state: the operation mode, power mode and units are read from it.
+// The clock selection cannot be read back, so it is assumed to be internal
+// unless WithExternalCrystal is given. Other initialization options are
+// ignored.
+func WithoutInit() Option {
+	return func(config *config) {
+		config.skipInit = true
+	}
+}
+
+// WithExternalCrystal selects the external crystal during initialization.
+// With WithoutInit it declares the clock the sensor already uses instead,
+// so later writes of SYS_TRIGGER keep it selected.
+func WithExternalCrystal(b bool) Option {
+	return func(config *config) {
+		config.externalCrystal = b
+	}
+}
+
+// WithCalibrationOffsets writes the offsets during initialization.
+// By default no offsets are written.
+func WithCalibrationOffsets(offsets CalibrationOffsets) Option {
+	return func(config *config) {
+		config.calibrationOffsets = offsets
+	}
+}
+
+// WithOperationMode sets the operation mode the sensor is left in after
+// initialization. It defaults to NDOF.
+func WithOperationMode(mode OperationMode) Option {
+	return func(config *config) {
+		config.operationMode = &mode
+	}
+}
+
+// WithPowerMode sets the power mode written during initialization.
+// It defaults to normal.
+func WithPowerMode(mode PowerMode) Option {
+	return func(config *config) {
+		config.powerMode = &mode
+	}
+}
+
+// WithTemperatureSource selects the sensor the temperature is measured by.
+// It defaults to the gyroscope.
+func WithTemperatureSource(source TemperatureSource) Option {
+	return func(config *config) {
+		config.temperatureSource = &source
+	}
+}
+
+// WithChunking limits I2C transfers to size bytes with delay between them,
+// so long reads such as the calibration offsets survive clock stretching
+// on adapters that handle it poorly (see i2c.WithChunking).
+func WithChunking(size int, delay time.Duration) Option {
+	return func(config *config) {
+		config.chunkSize = size
+		config.chunkDelay = delay
+	}
+}
+
 func NewSensor(addr uint8, bus int, options ...Option) (*Sensor, error) {
+	return NewSensorContext(context.Background(), addr, bus, options...)
+}
+
+func NewSensorContext(ctx context.Context, addr uint8, bus int, options ...Option) (*Sensor, error) {
 	config := &config{}
 	for _, option := range options {
 		option(config)
 	}
 
-	i2cBus, err := i2c.NewBus(addr, bus, config.retryCount, config.retryTimeout)
+	i2cBus, err := i2c.NewBus(addr, bus, config.retryCount, config.retryTimeout, config.busOptions()...)
 	if err != nil {
 		return nil, err
 	}
 
-	sensor, err := NewSensorFromBus(i2cBus)
+	sensor, err := newSensor(ctx, i2cBus, config)
 	if err != nil {
+		i2cBus.Close()
 		return nil, err
 	}
 
-	err = sensor.init()
-	if err != nil {
-		return nil, err
+	return sensor, nil
+}
+
+func NewSensorFromBus(bus I2CBus, options ...Option) (*Sensor, error) {
+	return NewSensorFromBusContext(context.Background(), bus, options...)
+}
+
+// NewSensorFromBusContext initializes the sensor on bus. Options that
+// configure the I2C adapter are ignored.
+func NewSensorFromBusContext(ctx context.Context, bus I2CBus, options ...Option) (*Sensor, error) {
+	config := &config{}
+	for _, option := range options {
+		option(config)
 	}
 
-	return sensor, nil
+	return newSensor(ctx, bus, config)
 }
 
-func NewSensorFromBus(bus I2CBus) (*Sensor, error) {
+func newSensor(ctx context.Context, bus I2CBus, config *config) (*Sensor, error) {
 	sensor := &Sensor{
 		bus:    bus,
 		opMode: bno055OperationModeNdof,
+		page:   pageUnknown,
 	}
 
-	err := sensor.init()
+	err := sensor.init(ctx, config)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,255 @@
+package bno055_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kpeu3i/bno055"
+	"github.com/kpeu3i/bno055/bnotest"
+)
+
+var testOffsets = bno055.CalibrationOffsets{
+	0x01, 0x00, 0xFF, 0xFF, 0x03, 0x00, // ACC_OFFSET
+	0x04, 0x00, 0x05, 0x00, 0x06, 0x00, // MAG_OFFSET
+	0x07, 0x00, 0x08, 0x00, 0x09, 0x00, // GYR_OFFSET
+	0xE8, 0x03, // ACC_RADIUS
+	0xE0, 0x01, // MAG_RADIUS
+}
+
+func newTestSensor(t *testing.T, options ...bno055.Option) (*bno055.Sensor, *bnotest.Device) {
+	device := bnotest.NewDevice()
+
+	sensor, err := bno055.NewSensorFromBus(device, append([]bno055.Option{bno055.WithoutReset()}, options...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sensor, device
+}
+
+// Wraps a device and calls hook before each write; an error returned by
+// the hook fails the write
+type hookBus struct {
+	*bnotest.Device
+	hook func(page int, reg byte, buff []byte) error
+}
+
+func (b *hookBus) Write(reg byte, val byte) error {
+	return b.WriteBuffer(reg, []byte{val})
+}
+
+func (b *hookBus) WriteBuffer(reg byte, buff []byte) error {
+	if b.hook != nil {
+		err := b.hook(b.Page(), reg, buff)
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.Device.WriteBuffer(reg, buff)
+}
+
+func TestNewSensorFromBus(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	if device.Resets() != 0 {
+		t.Fatalf("device reset %d times with WithoutReset", device.Resets())
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeNDOF) {
+		t.Fatalf("device in operation mode 0x%02X, want NDOF", mode)
+	}
+
+	if mode := sensor.OperationMode(); mode != bno055.OperationModeNDOF {
+		t.Fatalf("OperationMode = %s, want NDOF", mode)
+	}
+
+	regs := []struct {
+		reg  byte
+		want byte
+	}{
+		{bno055.RegUnitSel, 0x00},
+		{bno055.RegPwrMode, 0x00},
+		{bno055.RegTempSource, 0x01},
+	}
+
+	for _, reg := range regs {
+		if val := device.Register(bnotest.Page0, reg.reg); val != reg.want {
+			t.Errorf("register 0x%02X = 0x%02X, want 0x%02X", reg.reg, val, reg.want)
+		}
+	}
+
+	want := bno055.Units{
+		Acceleration: bno055.UnitMetersPerSecondSquared,
+		AngularRate:  bno055.UnitDegreesPerSecond,
+		Angle:        bno055.UnitDegrees,
+		Temperature:  bno055.UnitCelsius,
+		Orientation:  bno055.OrientationWindows,
+	}
+
+	if units := sensor.Units(); units != want {
+		t.Fatalf("Units = %+v, want %+v", units, want)
+	}
+}
+
+func TestNewSensorFromBusReset(t *testing.T) {
+	device := bnotest.NewDevice()
+	device.SetRegister(bnotest.Page0, bno055.RegAxisMapConf, 0x21)
+
+	_, err := bno055.NewSensorFromBus(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if device.Resets() != 1 {
+		t.Fatalf("device reset %d times, want 1", device.Resets())
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegAxisMapConf); val != 0x24 {
+		t.Fatalf("AXIS_MAP_CONFIG = 0x%02X after reset, want 0x24", val)
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeNDOF) {
+		t.Fatalf("device in operation mode 0x%02X, want NDOF", mode)
+	}
+}
+
+func TestNewSensorFromBusExternalCrystal(t *testing.T) {
+	device := bnotest.NewDevice()
+
+	_, err := bno055.NewSensorFromBus(device,
+		bno055.WithoutReset(),
+		bno055.WithExternalCrystal(true),
+		bno055.WithOperationMode(bno055.OperationModeConfig),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegSysTrigger); val != bno055.SysTriggerClkSel {
+		t.Fatalf("SYS_TRIGGER = 0x%02X, want the external crystal selected", val)
+	}
+
+	// Attaching declares the crystal, so RST_INT keeps it selected
+	sensor, err := bno055.NewSensorFromBus(device, bno055.WithoutInit(), bno055.WithExternalCrystal(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sensor.ResetInterrupts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegSysTrigger); val != bno055.SysTriggerClkSel {
+		t.Fatalf("SYS_TRIGGER = 0x%02X after RST_INT, want the external crystal kept", val)
+	}
+}
+
+func TestEuler(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	device.SetEuler(90*16, -45*16, 8)
+
+	euler, err := sensor.Euler()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := bno055.Vector{X: 90, Y: -45, Z: 0.5, Unit: bno055.UnitDegrees}
+	if *euler != want {
+		t.Fatalf("Euler = %+v, want %+v", *euler, want)
+	}
+}
+
+func TestCalibration(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	device.SetCalibrationStatus(3, 3, 2, 1)
+	device.SetRegisters(bnotest.Page0, bno055.RegAccOffsetX, testOffsets)
+
+	offsets, status, err := sensor.Calibration()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(offsets, testOffsets) {
+		t.Fatalf("offsets = % X, want % X", []byte(offsets), []byte(testOffsets))
+	}
+
+	want := bno055.CalibrationStatus{System: 3, Gyroscope: 3, Accelerometer: 2, Magnetometer: 1}
+	if *status != want {
+		t.Fatalf("status = %+v, want %+v", *status, want)
+	}
+
+	if status.IsCalibrated() {
+		t.Fatal("IsCalibrated = true with an uncalibrated magnetometer")
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeNDOF) {
+		t.Fatalf("device left in operation mode 0x%02X, want NDOF", mode)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	err := sensor.Calibrate(testOffsets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offsets, _, err := sensor.Calibration()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(offsets, testOffsets) {
+		t.Fatalf("offsets = % X, want % X", []byte(offsets), []byte(testOffsets))
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeNDOF) {
+		t.Fatalf("device left in operation mode 0x%02X, want NDOF", mode)
+	}
+}
+
+func TestRemapAxis(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	config := &bno055.AxisConfig{X: 1, Y: 0, Z: 2, SignX: 1}
+
+	err := sensor.RemapAxis(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegAxisMapConf); val != 0x21 {
+		t.Fatalf("AXIS_MAP_CONFIG = 0x%02X, want 0x21", val)
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegAxisMapSign); val != 0x04 {
+		t.Fatalf("AXIS_MAP_SIGN = 0x%02X, want 0x04", val)
+	}
+
+	axisConfig, err := sensor.AxisConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *axisConfig != *config {
+		t.Fatalf("AxisConfig = %+v, want %+v", *axisConfig, *config)
+	}
+
+	err = sensor.RemapAxis(&bno055.AxisConfig{X: 0, Y: 0, Z: 2})
+	if err == nil {
+		t.Fatal("RemapAxis accepted an axis mapped twice")
+	}
+
+	if val := device.Register(bnotest.Page0, bno055.RegAxisMapConf); val != 0x21 {
+		t.Fatalf("AXIS_MAP_CONFIG = 0x%02X after a rejected remap, want 0x21", val)
+	}
+
+	if mode := device.OperationMode(); mode != byte(bno055.OperationModeNDOF) {
+		t.Fatalf("device left in operation mode 0x%02X, want NDOF", mode)
+	}
+}
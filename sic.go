@@ -0,0 +1,105 @@
+package bno055
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// 1.0 in the fixed-point format of the SIC matrix
+const sicScale = 1 << 14
+
+// SICMatrix is the soft iron calibration matrix applied to the magnetometer
+// data (see section 3.6.4.8 of the datasheet). Elements are stored row by
+// row as signed fixed-point numbers, where 16384 is 1.0.
+type SICMatrix [9]int16
+
+// IdentitySICMatrix returns the reset value of the matrix, which leaves the
+// magnetometer data unchanged.
+func IdentitySICMatrix() *SICMatrix {
+	return &SICMatrix{
+		sicScale, 0, 0,
+		0, sicScale, 0,
+		0, 0, sicScale,
+	}
+}
+
+// NewSICMatrix converts a matrix of floats, each of which must be in the
+// range [-2, 2).
+func NewSICMatrix(m [3][3]float64) (*SICMatrix, error) {
+	matrix := &SICMatrix{}
+
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			val := math.Round(m[row][col] * sicScale)
+			if math.IsNaN(val) || val < math.MinInt16 || val > math.MaxInt16 {
+				return nil, fmt.Errorf("bno055: SIC matrix element [%d][%d] = %g out of range [-2, 2)", row, col, m[row][col])
+			}
+
+			matrix[row*3+col] = int16(val)
+		}
+	}
+
+	return matrix, nil
+}
+
+func newSICMatrix(buf []byte) *SICMatrix {
+	matrix := &SICMatrix{}
+	for i := range matrix {
+		matrix[i] = int16(binary.LittleEndian.Uint16(buf[2*i:]))
+	}
+
+	return matrix
+}
+
+// Float converts the matrix to floats.
+func (m *SICMatrix) Float() [3][3]float64 {
+	var f [3][3]float64
+
+	for i, val := range m {
+		f[i/3][i%3] = float64(val) / sicScale
+	}
+
+	return f
+}
+
+func (m *SICMatrix) bytes() []byte {
+	buf := make([]byte, 18)
+	for i, val := range m {
+		binary.LittleEndian.PutUint16(buf[2*i:], uint16(val))
+	}
+
+	return buf
+}
+
+func (s *Sensor) SoftIronMatrix() (*SICMatrix, error) {
+	return s.SoftIronMatrixContext(context.Background())
+}
+
+func (s *Sensor) SoftIronMatrixContext(ctx context.Context) (*SICMatrix, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf := make([]byte, 18)
+	err := s.readBuffer(ctx, bno055SicMatrix0Lsb, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSICMatrix(buf), nil
+}
+
+func (s *Sensor) SetSoftIronMatrix(matrix *SICMatrix) error {
+	return s.SetSoftIronMatrixContext(context.Background(), matrix)
+}
+
+// SetSoftIronMatrixContext writes the SIC matrix in CONFIG mode.
+func (s *Sensor) SetSoftIronMatrixContext(ctx context.Context, matrix *SICMatrix) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.inConfigMode(ctx, func() error {
+		return s.writeBuffer(ctx, bno055SicMatrix0Lsb, matrix.bytes())
+	})
+}
@@ -0,0 +1,54 @@
+package bno055_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kpeu3i/bno055"
+)
+
+func TestNewSICMatrix(t *testing.T) {
+	identity := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+	matrix, err := bno055.NewSICMatrix(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *matrix != *bno055.IdentitySICMatrix() {
+		t.Fatalf("NewSICMatrix(identity) = %v, want %v", *matrix, *bno055.IdentitySICMatrix())
+	}
+
+	for _, val := range []float64{2, -2.1, math.Inf(1), math.NaN()} {
+		m := identity
+		m[1][2] = val
+
+		_, err := bno055.NewSICMatrix(m)
+		if err == nil {
+			t.Errorf("NewSICMatrix accepted %g", val)
+		}
+	}
+}
+
+func TestSetSoftIronMatrix(t *testing.T) {
+	sensor, _ := newTestSensor(t)
+
+	matrix, err := bno055.NewSICMatrix([3][3]float64{{1, 0.5, 0}, {0, -1, 0}, {0, 0, 1.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sensor.SetSoftIronMatrix(matrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := sensor.SoftIronMatrix()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *read != *matrix {
+		t.Fatalf("SoftIronMatrix = %v, want %v", *read, *matrix)
+	}
+}
@@ -0,0 +1,218 @@
+// Package tca9548a supports sensors behind a TCA9548A 8-channel I2C multiplexer.
+package tca9548a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/kpeu3i/bno055"
+)
+
+const (
+	// Default address of the multiplexer (A0..A2 low)
+	DefaultAddress = 0x70
+
+	Channels = 8
+)
+
+var ErrChannelClosed = errors.New("tca9548a: channel is closed")
+
+// Mux selects one multiplexer channel before each transaction on the
+// shared device bus. Access through all of its channels is serialized.
+//
+// The control register of the multiplexer is written through control,
+// e.g. an *i2c.Bus bound to DefaultAddress. The bus is the one bound to the
+// address of the devices behind the multiplexer.
+type Mux struct {
+	mu      sync.Mutex
+	control io.ByteWriter
+	bus     bno055.I2CBus
+	mask    int
+}
+
+func New(control io.ByteWriter, bus bno055.I2CBus) *Mux {
+	mux := &Mux{
+		control: control,
+		bus:     bus,
+		mask:    -1,
+	}
+
+	return mux
+}
+
+// Channel returns a bus for the devices connected to channel n (0..7).
+// It can be passed to bno055.NewSensorFromBus.
+func (m *Mux) Channel(n int) (*Channel, error) {
+	if n < 0 || n >= Channels {
+		return nil, fmt.Errorf("tca9548a: invalid channel %d", n)
+	}
+
+	channel := &Channel{
+		mux:  m,
+		mask: 1 << uint(n),
+	}
+
+	return channel, nil
+}
+
+// Disable deselects all channels.
+func (m *Mux) Disable() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.selectMask(0)
+}
+
+// Close disables all channels and closes the device bus and, if it can be
+// closed, the control bus.
+func (m *Mux) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err := m.selectMask(0)
+
+	closeErr := m.bus.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if closer, ok := m.control.(io.Closer); ok {
+		closeErr = closer.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// Must be called with the lock held
+func (m *Mux) selectMask(mask byte) error {
+	if m.mask == int(mask) {
+		return nil
+	}
+
+	err := m.control.WriteByte(mask)
+	if err != nil {
+		// The state of the control register is unknown now
+		m.mask = -1
+		return err
+	}
+
+	m.mask = int(mask)
+
+	return nil
+}
+
+// Channel is the bus of a single multiplexer channel.
+type Channel struct {
+	mux    *Mux
+	mask   byte
+	closed bool
+}
+
+func (c *Channel) Read(reg byte) (byte, error) {
+	return c.ReadContext(context.Background(), reg)
+}
+
+func (c *Channel) ReadContext(ctx context.Context, reg byte) (byte, error) {
+	c.mux.mu.Lock()
+	defer c.mux.mu.Unlock()
+
+	err := c.selectChannel()
+	if err != nil {
+		return 0, err
+	}
+
+	if bus, ok := c.mux.bus.(bno055.I2CBusContext); ok {
+		return bus.ReadContext(ctx, reg)
+	}
+
+	return c.mux.bus.Read(reg)
+}
+
+func (c *Channel) Write(reg byte, val byte) error {
+	return c.WriteContext(context.Background(), reg, val)
+}
+
+func (c *Channel) WriteContext(ctx context.Context, reg byte, val byte) error {
+	c.mux.mu.Lock()
+	defer c.mux.mu.Unlock()
+
+	err := c.selectChannel()
+	if err != nil {
+		return err
+	}
+
+	if bus, ok := c.mux.bus.(bno055.I2CBusContext); ok {
+		return bus.WriteContext(ctx, reg, val)
+	}
+
+	return c.mux.bus.Write(reg, val)
+}
+
+func (c *Channel) ReadBuffer(reg byte, buff []byte) error {
+	return c.ReadBufferContext(context.Background(), reg, buff)
+}
+
+func (c *Channel) ReadBufferContext(ctx context.Context, reg byte, buff []byte) error {
+	c.mux.mu.Lock()
+	defer c.mux.mu.Unlock()
+
+	err := c.selectChannel()
+	if err != nil {
+		return err
+	}
+
+	if bus, ok := c.mux.bus.(bno055.I2CBusContext); ok {
+		return bus.ReadBufferContext(ctx, reg, buff)
+	}
+
+	return c.mux.bus.ReadBuffer(reg, buff)
+}
+
+func (c *Channel) WriteBuffer(reg byte, buff []byte) error {
+	return c.WriteBufferContext(context.Background(), reg, buff)
+}
+
+func (c *Channel) WriteBufferContext(ctx context.Context, reg byte, buff []byte) error {
+	c.mux.mu.Lock()
+	defer c.mux.mu.Unlock()
+
+	err := c.selectChannel()
+	if err != nil {
+		return err
+	}
+
+	if bus, ok := c.mux.bus.(bno055.I2CBusContext); ok {
+		return bus.WriteBufferContext(ctx, reg, buff)
+	}
+
+	return c.mux.bus.WriteBuffer(reg, buff)
+}
+
+// Close releases the channel only; the shared buses are closed by Mux.Close.
+func (c *Channel) Close() error {
+	c.mux.mu.Lock()
+	defer c.mux.mu.Unlock()
+
+	if c.closed {
+		return ErrChannelClosed
+	}
+
+	c.closed = true
+
+	return nil
+}
+
+// Must be called with the mux lock held
+func (c *Channel) selectChannel() error {
+	if c.closed {
+		return ErrChannelClosed
+	}
+
+	return c.mux.selectMask(c.mask)
+}
@@ -0,0 +1,161 @@
+package tca9548a_test
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kpeu3i/bno055"
+	"github.com/kpeu3i/bno055/bnotest"
+	"github.com/kpeu3i/bno055/tca9548a"
+)
+
+// Counts transactions that overlap on the shared device bus
+type exclusiveBus struct {
+	bno055.I2CBus
+	active   int32
+	overlaps int32
+}
+
+func (b *exclusiveBus) enter() func() {
+	if atomic.AddInt32(&b.active, 1) > 1 {
+		atomic.AddInt32(&b.overlaps, 1)
+	}
+
+	// Widen the window for another channel to interleave
+	time.Sleep(50 * time.Microsecond)
+
+	return func() {
+		atomic.AddInt32(&b.active, -1)
+	}
+}
+
+func (b *exclusiveBus) Read(reg byte) (byte, error) {
+	defer b.enter()()
+
+	return b.I2CBus.Read(reg)
+}
+
+func (b *exclusiveBus) ReadBuffer(reg byte, buff []byte) error {
+	defer b.enter()()
+
+	return b.I2CBus.ReadBuffer(reg, buff)
+}
+
+func newMux(t *testing.T, channels int) (*tca9548a.Mux, *bnotest.Mux, []*tca9548a.Channel) {
+	sim := bnotest.NewMux()
+	mux := tca9548a.New(sim, sim)
+
+	var chans []*tca9548a.Channel
+	for n := 0; n < channels; n++ {
+		device := bnotest.NewDevice()
+		device.SetEuler(int16(n), int16(n), int16(n))
+		sim.Attach(n, device)
+
+		channel, err := mux.Channel(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chans = append(chans, channel)
+	}
+
+	return mux, sim, chans
+}
+
+func TestMuxSkipsRedundantSelections(t *testing.T) {
+	mux, sim, chans := newMux(t, 2)
+
+	steps := []struct {
+		channel    int
+		selections int
+		mask       byte
+	}{
+		{0, 1, 0x01},
+		{0, 1, 0x01},
+		{1, 2, 0x02},
+		{1, 2, 0x02},
+		{0, 3, 0x01},
+	}
+
+	for i, step := range steps {
+		_, err := chans[step.channel].Read(bno055.RegChipID)
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+
+		if sim.Selections() != step.selections || sim.Mask() != step.mask {
+			t.Fatalf("step %d: %d selections with mask 0x%02X, want %d with 0x%02X", i, sim.Selections(), sim.Mask(), step.selections, step.mask)
+		}
+	}
+
+	err := mux.Disable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = mux.Disable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sim.Selections() != 4 || sim.Mask() != 0 {
+		t.Fatalf("%d selections with mask 0x%02X after Disable, want 4 with 0x00", sim.Selections(), sim.Mask())
+	}
+}
+
+func TestMuxSerializesChannels(t *testing.T) {
+	sim := bnotest.NewMux()
+	bus := &exclusiveBus{I2CBus: sim}
+	mux := tca9548a.New(sim, bus)
+
+	const channels = 4
+
+	var wg sync.WaitGroup
+	errs := make(chan error, channels)
+
+	for n := 0; n < channels; n++ {
+		device := bnotest.NewDevice()
+		device.SetEuler(int16(n), int16(n), int16(n))
+		sim.Attach(n, device)
+
+		channel, err := mux.Channel(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []byte{byte(n), 0, byte(n), 0, byte(n), 0}
+
+		wg.Add(1)
+		go func(channel *tca9548a.Channel) {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				buf := make([]byte, 6)
+				err := channel.ReadBuffer(bno055.RegEulHeading, buf)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				if !bytes.Equal(buf, want) {
+					t.Errorf("read % X from the wrong channel, want % X", buf, want)
+					return
+				}
+			}
+		}(channel)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	if overlaps := atomic.LoadInt32(&bus.overlaps); overlaps != 0 {
+		t.Fatalf("%d transactions overlapped on the device bus", overlaps)
+	}
+}
@@ -0,0 +1,182 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kpeu3i/bno055"
+)
+
+// Recorder wraps a bus and logs every call made through it. Contexts are
+// passed on to the wrapped bus if it implements bno055.I2CBusContext.
+type Recorder struct {
+	mu     sync.Mutex
+	bus    bno055.I2CBus
+	enc    *json.Encoder
+	closer io.Closer
+	err    error
+}
+
+func NewRecorder(bus bno055.I2CBus, w io.Writer) *Recorder {
+	recorder := &Recorder{
+		bus: bus,
+		enc: json.NewEncoder(w),
+	}
+
+	return recorder
+}
+
+// Create records the calls made through bus to the named file.
+// The file is closed together with the bus.
+func Create(bus bno055.I2CBus, path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := NewRecorder(bus, file)
+	recorder.closer = file
+
+	return recorder, nil
+}
+
+func (r *Recorder) Read(reg byte) (byte, error) {
+	return r.ReadContext(context.Background(), reg)
+}
+
+func (r *Recorder) ReadContext(ctx context.Context, reg byte) (byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := time.Now()
+
+	var val byte
+	var err error
+	if bus, ok := r.bus.(bno055.I2CBusContext); ok {
+		val, err = bus.ReadContext(ctx, reg)
+	} else {
+		val, err = r.bus.Read(reg)
+	}
+
+	r.record(start, &Entry{Op: OpRead, Reg: reg, Len: 1, Data: []byte{val}}, err)
+
+	return val, err
+}
+
+func (r *Recorder) Write(reg byte, val byte) error {
+	return r.WriteContext(context.Background(), reg, val)
+}
+
+func (r *Recorder) WriteContext(ctx context.Context, reg byte, val byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := time.Now()
+
+	var err error
+	if bus, ok := r.bus.(bno055.I2CBusContext); ok {
+		err = bus.WriteContext(ctx, reg, val)
+	} else {
+		err = r.bus.Write(reg, val)
+	}
+
+	r.record(start, &Entry{Op: OpWrite, Reg: reg, Data: []byte{val}}, err)
+
+	return err
+}
+
+func (r *Recorder) ReadBuffer(reg byte, buff []byte) error {
+	return r.ReadBufferContext(context.Background(), reg, buff)
+}
+
+func (r *Recorder) ReadBufferContext(ctx context.Context, reg byte, buff []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := time.Now()
+
+	var err error
+	if bus, ok := r.bus.(bno055.I2CBusContext); ok {
+		err = bus.ReadBufferContext(ctx, reg, buff)
+	} else {
+		err = r.bus.ReadBuffer(reg, buff)
+	}
+
+	data := make([]byte, len(buff))
+	copy(data, buff)
+
+	r.record(start, &Entry{Op: OpReadBuffer, Reg: reg, Len: len(buff), Data: data}, err)
+
+	return err
+}
+
+func (r *Recorder) WriteBuffer(reg byte, buff []byte) error {
+	return r.WriteBufferContext(context.Background(), reg, buff)
+}
+
+func (r *Recorder) WriteBufferContext(ctx context.Context, reg byte, buff []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := time.Now()
+
+	var err error
+	if bus, ok := r.bus.(bno055.I2CBusContext); ok {
+		err = bus.WriteBufferContext(ctx, reg, buff)
+	} else {
+		err = r.bus.WriteBuffer(reg, buff)
+	}
+
+	data := make([]byte, len(buff))
+	copy(data, buff)
+
+	r.record(start, &Entry{Op: OpWriteBuffer, Reg: reg, Data: data}, err)
+
+	return err
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := time.Now()
+	err := r.bus.Close()
+
+	r.record(start, &Entry{Op: OpClose}, err)
+
+	if r.closer != nil {
+		closeErr := r.closer.Close()
+		if closeErr != nil && r.err == nil {
+			r.err = closeErr
+		}
+	}
+
+	return err
+}
+
+// Err returns the first error that occurred while writing the recording.
+// Recording failures never affect the calls made through the bus.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+func (r *Recorder) record(start time.Time, entry *Entry, err error) {
+	entry.Time = start
+	entry.Duration = time.Since(start)
+
+	if err != nil {
+		entry.Err = err.Error()
+	}
+
+	encErr := r.enc.Encode(entry)
+	if encErr != nil && r.err == nil {
+		r.err = encErr
+	}
+}
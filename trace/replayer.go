@@ -0,0 +1,162 @@
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// MismatchError is returned by the replayer when a call differs from the
+// one found at the same position in the recording.
+type MismatchError struct {
+	Index int
+	Want  *Entry
+	Got   *Entry
+}
+
+func (e *MismatchError) Error() string {
+	if e.Want == nil {
+		return fmt.Sprintf("trace: call %d: unexpected %s after end of recording", e.Index, e.Got)
+	}
+
+	return fmt.Sprintf("trace: call %d: got %s, want %s", e.Index, e.Got, e.Want)
+}
+
+// Replayer implements bno055.I2CBus by serving recorded responses back in
+// order. Recorded errors are returned with their original message only.
+type Replayer struct {
+	mu      sync.Mutex
+	entries []*Entry
+	next    int
+}
+
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	var entries []*Entry
+
+	dec := json.NewDecoder(r)
+	for {
+		entry := &Entry{}
+
+		err := dec.Decode(entry)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	replayer := &Replayer{
+		entries: entries,
+	}
+
+	return replayer, nil
+}
+
+// Open replays the recording stored in the named file.
+func Open(path string) (*Replayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return NewReplayer(file)
+}
+
+func (r *Replayer) Read(reg byte) (byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, err := r.serve(&Entry{Op: OpRead, Reg: reg, Len: 1})
+	if entry == nil {
+		return 0, err
+	}
+
+	var val byte
+	if len(entry.Data) > 0 {
+		val = entry.Data[0]
+	}
+
+	return val, err
+}
+
+func (r *Replayer) Write(reg byte, val byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err := r.serve(&Entry{Op: OpWrite, Reg: reg, Data: []byte{val}})
+
+	return err
+}
+
+func (r *Replayer) ReadBuffer(reg byte, buff []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, err := r.serve(&Entry{Op: OpReadBuffer, Reg: reg, Len: len(buff)})
+	if entry == nil {
+		return err
+	}
+
+	copy(buff, entry.Data)
+
+	return err
+}
+
+func (r *Replayer) WriteBuffer(reg byte, buff []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err := r.serve(&Entry{Op: OpWriteBuffer, Reg: reg, Data: buff})
+
+	return err
+}
+
+// Close consumes a recorded close call if it is next in the recording.
+func (r *Replayer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next < len(r.entries) && r.entries[r.next].Op == OpClose {
+		_, err := r.serve(&Entry{Op: OpClose})
+		return err
+	}
+
+	return nil
+}
+
+// Remaining returns the number of recorded calls not replayed yet.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.entries) - r.next
+}
+
+// Returns the next recorded entry and its error, or a mismatch error
+// (with a nil entry) if the request does not match the recording
+func (r *Replayer) serve(got *Entry) (*Entry, error) {
+	if r.next >= len(r.entries) {
+		return nil, &MismatchError{Index: r.next, Got: got}
+	}
+
+	want := r.entries[r.next]
+	if !want.matches(got) {
+		return nil, &MismatchError{Index: r.next, Want: want, Got: got}
+	}
+
+	r.next++
+
+	if want.Err != "" {
+		return want, errors.New(want.Err)
+	}
+
+	return want, nil
+}
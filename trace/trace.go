@@ -0,0 +1,88 @@
+// Package trace records bus transactions to a file and replays them later,
+// so that a problem captured on a device can be reproduced without hardware.
+package trace
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type Op string
+
+const (
+	OpRead        Op = "read"
+	OpWrite       Op = "write"
+	OpReadBuffer  Op = "read_buffer"
+	OpWriteBuffer Op = "write_buffer"
+	OpClose       Op = "close"
+)
+
+// Entry is a single recorded bus call. The recording is a sequence of
+// entries encoded as JSON, one per line.
+//
+// For reads Data holds the returned bytes, for writes the bytes sent.
+type Entry struct {
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration"`
+	Op       Op            `json:"op"`
+	Reg      byte          `json:"reg"`
+	Len      int           `json:"len,omitempty"`
+	Data     HexBytes      `json:"data,omitempty"`
+	Err      string        `json:"err,omitempty"`
+}
+
+func (e *Entry) String() string {
+	switch e.Op {
+	case OpRead, OpReadBuffer:
+		return fmt.Sprintf("%s(0x%02X, %d)", e.Op, e.Reg, e.Len)
+	case OpWrite, OpWriteBuffer:
+		return fmt.Sprintf("%s(0x%02X, %s)", e.Op, e.Reg, e.Data)
+	}
+
+	return string(e.Op)
+}
+
+// Checks whether the request part of two entries is the same
+func (e *Entry) matches(other *Entry) bool {
+	if e.Op != other.Op || e.Reg != other.Reg || e.Len != other.Len {
+		return false
+	}
+
+	if e.Op == OpWrite || e.Op == OpWriteBuffer {
+		return bytes.Equal(e.Data, other.Data)
+	}
+
+	return true
+}
+
+// HexBytes is a byte slice that is encoded as a hex string.
+type HexBytes []byte
+
+func (b HexBytes) String() string {
+	return hex.EncodeToString(b)
+}
+
+func (b HexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(b))
+}
+
+func (b *HexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+
+	*b = decoded
+
+	return nil
+}
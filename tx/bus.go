@@ -0,0 +1,122 @@
+// Package tx builds a bus for the sensor on top of a single write-then-read
+// transfer primitive, the shape most Go I2C libraries expose.
+package tx
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/kpeu3i/bno055/retry"
+)
+
+// Conn performs one I2C transaction: it writes w, then reads len(r) bytes
+// into r with a repeated start. Either w or r may be empty.
+type Conn interface {
+	Tx(w, r []byte) error
+}
+
+// Func adapts an ordinary function to the Conn interface.
+type Func func(w, r []byte) error
+
+func (f Func) Tx(w, r []byte) error {
+	return f(w, r)
+}
+
+type Option func(config *config)
+
+type config struct {
+	retryPolicy retry.Policy
+}
+
+// Bus implements bno055.I2CBus through Conn.Tx. If conn implements
+// io.Closer, it is closed together with the bus.
+type Bus struct {
+	policy retry.Policy
+	mu     sync.Mutex
+	conn   Conn
+}
+
+// WithRetryPolicy sets the policy used to retry failed transactions.
+// By default they are not retried.
+func WithRetryPolicy(policy retry.Policy) Option {
+	return func(config *config) {
+		config.retryPolicy = policy
+	}
+}
+
+func NewBus(conn Conn, options ...Option) *Bus {
+	config := &config{}
+	for _, option := range options {
+		option(config)
+	}
+
+	if config.retryPolicy == nil {
+		config.retryPolicy = retry.Fixed(0, 0)
+	}
+
+	txBus := &Bus{
+		policy: config.retryPolicy,
+		conn:   conn,
+	}
+
+	return txBus
+}
+
+func (b *Bus) Read(reg byte) (byte, error) {
+	return b.ReadContext(context.Background(), reg)
+}
+
+func (b *Bus) ReadContext(ctx context.Context, reg byte) (byte, error) {
+	buf := make([]byte, 1)
+
+	err := b.tx(ctx, []byte{reg}, buf)
+
+	return buf[0], err
+}
+
+func (b *Bus) Write(reg byte, val byte) error {
+	return b.WriteContext(context.Background(), reg, val)
+}
+
+func (b *Bus) WriteContext(ctx context.Context, reg byte, val byte) error {
+	return b.tx(ctx, []byte{reg, val}, nil)
+}
+
+func (b *Bus) ReadBuffer(reg byte, buff []byte) error {
+	return b.ReadBufferContext(context.Background(), reg, buff)
+}
+
+func (b *Bus) ReadBufferContext(ctx context.Context, reg byte, buff []byte) error {
+	return b.tx(ctx, []byte{reg}, buff)
+}
+
+func (b *Bus) WriteBuffer(reg byte, buff []byte) error {
+	return b.WriteBufferContext(context.Background(), reg, buff)
+}
+
+func (b *Bus) WriteBufferContext(ctx context.Context, reg byte, buff []byte) error {
+	return b.tx(ctx, append([]byte{reg}, buff...), nil)
+}
+
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if closer, ok := b.conn.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+func (b *Bus) tx(ctx context.Context, w, r []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	err := b.policy.Do(ctx, func() error {
+		return b.conn.Tx(w, r)
+	})
+
+	return err
+}
@@ -0,0 +1,349 @@
+//go:build linux
+// +build linux
+
+package uart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/kpeu3i/bno055/retry"
+)
+
+const (
+	tcflsh  = 0x540B
+	tciflsh = 0x0
+	cbaud   = 0x100F
+
+	startByte        = 0xAA
+	readResponseByte = 0xBB
+	statusByte       = 0xEE
+
+	cmdWrite = 0x00
+	cmdRead  = 0x01
+
+	// Maximum number of bytes the sensor accepts in one transfer
+	maxLength = 128
+
+	// Read timeout in tenths of a second (VTIME)
+	readTimeout = 10
+)
+
+// StatusError is a status code returned by the sensor in a 0xEE response.
+type StatusError byte
+
+const (
+	ErrReadFail                StatusError = 0x02
+	ErrWriteFail               StatusError = 0x03
+	ErrRegmapInvalidAddress    StatusError = 0x04
+	ErrRegmapWriteDisabled     StatusError = 0x05
+	ErrWrongStartByte          StatusError = 0x06
+	ErrBusOverRun              StatusError = 0x07
+	ErrMaxLength               StatusError = 0x08
+	ErrMinLength               StatusError = 0x09
+	ErrReceiveCharacterTimeout StatusError = 0x0A
+
+	writeSuccess = 0x01
+)
+
+var ErrTimeout = errors.New("uart: response timeout")
+
+// Temporary reports whether the request may succeed if sent again.
+func (e StatusError) Temporary() bool {
+	switch e {
+	case ErrRegmapInvalidAddress, ErrRegmapWriteDisabled, ErrMaxLength, ErrMinLength:
+		return false
+	}
+
+	return true
+}
+
+func (e StatusError) Error() string {
+	switch e {
+	case ErrReadFail:
+		return "uart: read fail"
+	case ErrWriteFail:
+		return "uart: write fail"
+	case ErrRegmapInvalidAddress:
+		return "uart: register map invalid address"
+	case ErrRegmapWriteDisabled:
+		return "uart: register map write disabled"
+	case ErrWrongStartByte:
+		return "uart: wrong start byte"
+	case ErrBusOverRun:
+		return "uart: bus over run"
+	case ErrMaxLength:
+		return "uart: max length error"
+	case ErrMinLength:
+		return "uart: min length error"
+	case ErrReceiveCharacterTimeout:
+		return "uart: receive character timeout"
+	}
+
+	return fmt.Sprintf("uart: unknown status 0x%02X", byte(e))
+}
+
+type Option func(config *config)
+
+type config struct {
+	retryPolicy retry.Policy
+}
+
+type Bus struct {
+	policy retry.Policy
+	mu     sync.Mutex
+	rc     *os.File
+}
+
+// WithRetryPolicy overrides the fixed retry count and timeout passed to NewBus.
+func WithRetryPolicy(policy retry.Policy) Option {
+	return func(config *config) {
+		config.retryPolicy = policy
+	}
+}
+
+func NewBus(path string, retryCount int, retryTimeout time.Duration, options ...Option) (*Bus, error) {
+	config := &config{}
+	for _, option := range options {
+		option(config)
+	}
+
+	if config.retryPolicy == nil {
+		config.retryPolicy = retry.Fixed(retryCount, retryTimeout)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|syscall.O_NOCTTY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	err = makeRaw(file.Fd())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	uartBus := &Bus{
+		policy: config.retryPolicy,
+		rc:     file,
+	}
+
+	return uartBus, nil
+}
+
+func (b *Bus) Read(reg byte) (byte, error) {
+	return b.ReadContext(context.Background(), reg)
+}
+
+func (b *Bus) ReadContext(ctx context.Context, reg byte) (byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	buf := make([]byte, 1)
+
+	err := b.policy.Do(ctx, func() error {
+		return b.read(reg, buf)
+	})
+
+	return buf[0], err
+}
+
+func (b *Bus) Write(reg byte, val byte) error {
+	return b.WriteContext(context.Background(), reg, val)
+}
+
+func (b *Bus) WriteContext(ctx context.Context, reg byte, val byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	err := b.policy.Do(ctx, func() error {
+		return b.write(reg, []byte{val})
+	})
+
+	return err
+}
+
+func (b *Bus) ReadBuffer(reg byte, buff []byte) error {
+	return b.ReadBufferContext(context.Background(), reg, buff)
+}
+
+func (b *Bus) ReadBufferContext(ctx context.Context, reg byte, buff []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for len(buff) > 0 {
+		n := len(buff)
+		if n > maxLength {
+			n = maxLength
+		}
+
+		err := b.policy.Do(ctx, func() error {
+			return b.read(reg, buff[:n])
+		})
+		if err != nil {
+			return err
+		}
+
+		reg += byte(n)
+		buff = buff[n:]
+	}
+
+	return nil
+}
+
+func (b *Bus) WriteBuffer(reg byte, buff []byte) error {
+	return b.WriteBufferContext(context.Background(), reg, buff)
+}
+
+func (b *Bus) WriteBufferContext(ctx context.Context, reg byte, buff []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for len(buff) > 0 {
+		n := len(buff)
+		if n > maxLength {
+			n = maxLength
+		}
+
+		err := b.policy.Do(ctx, func() error {
+			return b.write(reg, buff[:n])
+		})
+		if err != nil {
+			return err
+		}
+
+		reg += byte(n)
+		buff = buff[n:]
+	}
+
+	return nil
+}
+
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.rc.Close()
+}
+
+func (b *Bus) read(reg byte, buff []byte) error {
+	err := b.flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = b.rc.Write([]byte{startByte, cmdRead, reg, byte(len(buff))})
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, 2)
+	err = b.readFull(header)
+	if err != nil {
+		return err
+	}
+
+	switch header[0] {
+	case readResponseByte:
+		if int(header[1]) != len(buff) {
+			return fmt.Errorf("uart: unexpected response length %d, want %d", header[1], len(buff))
+		}
+
+		return b.readFull(buff)
+	case statusByte:
+		return StatusError(header[1])
+	}
+
+	return fmt.Errorf("uart: unexpected response byte 0x%02X", header[0])
+}
+
+func (b *Bus) write(reg byte, buff []byte) error {
+	err := b.flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = b.rc.Write(append([]byte{startByte, cmdWrite, reg, byte(len(buff))}, buff...))
+	if err != nil {
+		return err
+	}
+
+	resp := make([]byte, 2)
+	err = b.readFull(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp[0] != statusByte {
+		return fmt.Errorf("uart: unexpected response byte 0x%02X", resp[0])
+	}
+
+	if resp[1] != writeSuccess {
+		return StatusError(resp[1])
+	}
+
+	return nil
+}
+
+func (b *Bus) readFull(buff []byte) error {
+	_, err := io.ReadFull(b.rc, buff)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrTimeout
+	}
+
+	return err
+}
+
+// Discards any stale bytes left over from a previous failed transaction
+func (b *Bus) flush() error {
+	return ioctlInt(b.rc.Fd(), tcflsh, tciflsh)
+}
+
+// Configures the port for 115200 baud, 8N1, raw mode (see section 4.7)
+func makeRaw(fd uintptr) error {
+	var t syscall.Termios
+
+	err := ioctl(fd, syscall.TCGETS, unsafe.Pointer(&t))
+	if err != nil {
+		return err
+	}
+
+	t.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	t.Oflag &^= syscall.OPOST
+	t.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	t.Cflag &^= syscall.CSIZE | syscall.PARENB | syscall.CSTOPB | cbaud
+	t.Cflag |= syscall.CS8 | syscall.CREAD | syscall.CLOCAL | syscall.B115200
+	t.Ispeed = syscall.B115200
+	t.Ospeed = syscall.B115200
+	t.Cc[syscall.VMIN] = 0
+	t.Cc[syscall.VTIME] = readTimeout
+
+	return ioctl(fd, syscall.TCSETS, unsafe.Pointer(&t))
+}
+
+// The pointer is converted in the call expression, so the memory it refers
+// to stays valid until the syscall returns
+func ioctl(fd, cmd uintptr, arg unsafe.Pointer) error {
+	_, _, err := syscall.Syscall6(syscall.SYS_IOCTL, fd, cmd, uintptr(arg), 0, 0, 0)
+	if err != 0 {
+		return err
+	}
+
+	return nil
+}
+
+func ioctlInt(fd, cmd, arg uintptr) error {
+	_, _, err := syscall.Syscall6(syscall.SYS_IOCTL, fd, cmd, arg, 0, 0, 0)
+	if err != 0 {
+		return err
+	}
+
+	return nil
+}
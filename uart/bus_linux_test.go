@@ -0,0 +1,172 @@
+package uart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// Opens a pseudo-terminal pair and returns the master and the slave path
+func openPTY(t *testing.T) (*os.File, string) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pseudo-terminals unavailable: %v", err)
+	}
+
+	var unlock int32
+	err = ioctl(master.Fd(), syscall.TIOCSPTLCK, unsafe.Pointer(&unlock))
+	if err != nil {
+		master.Close()
+		t.Fatal(err)
+	}
+
+	var n uint32
+	err = ioctl(master.Fd(), syscall.TIOCGPTN, unsafe.Pointer(&n))
+	if err != nil {
+		master.Close()
+		t.Fatal(err)
+	}
+
+	return master, fmt.Sprintf("/dev/pts/%d", n)
+}
+
+// Reads one request from the master side and answers it with resp
+func respond(t *testing.T, master *os.File, want []byte, resp []byte) <-chan error {
+	done := make(chan error, 1)
+
+	go func() {
+		req := make([]byte, len(want))
+		_, err := io.ReadFull(master, req)
+		if err != nil {
+			done <- err
+			return
+		}
+
+		if !bytes.Equal(req, want) {
+			done <- fmt.Errorf("request % X, want % X", req, want)
+			return
+		}
+
+		_, err = master.Write(resp)
+		done <- err
+	}()
+
+	return done
+}
+
+func newTestBus(t *testing.T) (*Bus, *os.File, func()) {
+	master, path := openPTY(t)
+
+	bus, err := NewBus(path, 0, 0)
+	if err != nil {
+		master.Close()
+		t.Fatal(err)
+	}
+
+	closer := func() {
+		bus.Close()
+		master.Close()
+	}
+
+	return bus, master, closer
+}
+
+func TestBusRead(t *testing.T) {
+	bus, master, closer := newTestBus(t)
+	defer closer()
+
+	done := respond(t, master, []byte{startByte, cmdRead, 0x00, 0x01}, []byte{readResponseByte, 0x01, 0xA0})
+
+	val, err := bus.Read(0x00)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if val != 0xA0 {
+		t.Fatalf("Read = 0x%02X, want 0xA0", val)
+	}
+}
+
+func TestBusReadBuffer(t *testing.T) {
+	bus, master, closer := newTestBus(t)
+	defer closer()
+
+	done := respond(t, master, []byte{startByte, cmdRead, 0x1A, 0x06}, []byte{readResponseByte, 0x06, 1, 2, 3, 4, 5, 6})
+
+	buf := make([]byte, 6)
+	err := bus.ReadBuffer(0x1A, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf, []byte{1, 2, 3, 4, 5, 6}) {
+		t.Fatalf("ReadBuffer = % X", buf)
+	}
+}
+
+func TestBusWrite(t *testing.T) {
+	bus, master, closer := newTestBus(t)
+	defer closer()
+
+	done := respond(t, master, []byte{startByte, cmdWrite, 0x3D, 0x01, 0x0C}, []byte{statusByte, writeSuccess})
+
+	err := bus.Write(0x3D, 0x0C)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBusStatusError(t *testing.T) {
+	bus, master, closer := newTestBus(t)
+	defer closer()
+
+	done := respond(t, master, []byte{startByte, cmdWrite, 0x55, 0x01, 0x00}, []byte{statusByte, byte(ErrRegmapWriteDisabled)})
+
+	err := bus.Write(0x55, 0x00)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if !errors.Is(err, ErrRegmapWriteDisabled) {
+		t.Fatalf("Write error = %v, want %v", err, ErrRegmapWriteDisabled)
+	}
+}
+
+func TestBusTimeout(t *testing.T) {
+	bus, master, closer := newTestBus(t)
+	defer closer()
+
+	done := respond(t, master, []byte{startByte, cmdRead, 0x00, 0x01}, nil)
+
+	start := time.Now()
+	_, err := bus.Read(0x00)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if err != ErrTimeout {
+		t.Fatalf("Read error = %v, want %v", err, ErrTimeout)
+	}
+
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Fatalf("Read timed out after %v, want about 1s", elapsed)
+	}
+}
@@ -0,0 +1,8 @@
+// Package uart implements the BNO055 UART protocol (see section 4.7 of the datasheet).
+// The transport uses Linux termios ioctls and is only built on Linux.
+//
+// Each read from the port blocks for up to one second (VTIME) waiting for the
+// response. The context passed to the Context methods is only checked between
+// attempts, so a deadline shorter than one second takes effect once the
+// pending read times out.
+package uart
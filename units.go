@@ -0,0 +1,198 @@
+package bno055
+
+import (
+	"context"
+	"fmt"
+)
+
+// Unit is the unit a value is reported in.
+type Unit string
+
+const (
+	UnitMetersPerSecondSquared Unit = "m/s²"
+	UnitMilliG                 Unit = "mg"
+	UnitDegreesPerSecond       Unit = "dps"
+	UnitRadiansPerSecond       Unit = "rps"
+	UnitDegrees                Unit = "°"
+	UnitRadians                Unit = "rad"
+	UnitCelsius                Unit = "°C"
+	UnitFahrenheit             Unit = "°F"
+	UnitMicroTesla             Unit = "µT"
+)
+
+// Orientation selects the pitch convention of the Euler angles (see section 3.6.1).
+type Orientation byte
+
+const (
+	// Pitch turns clockwise from -180° to +180°
+	OrientationWindows Orientation = 0
+	// Pitch turns counterclockwise from +180° to -180°
+	OrientationAndroid Orientation = 1
+)
+
+// Units configures the UNIT_SEL register (see section 3.6.1 of the datasheet).
+// Empty fields select the datasheet defaults: m/s², dps, degrees and Celsius.
+type Units struct {
+	// UnitMetersPerSecondSquared or UnitMilliG, used by the accelerometer,
+	// linear acceleration and gravity outputs
+	Acceleration Unit `json:"acceleration"`
+	// UnitDegreesPerSecond or UnitRadiansPerSecond
+	AngularRate Unit `json:"angular_rate"`
+	// UnitDegrees or UnitRadians, used by the Euler angles
+	Angle Unit `json:"angle"`
+	// UnitCelsius or UnitFahrenheit
+	Temperature Unit `json:"temperature"`
+
+	Orientation Orientation `json:"orientation"`
+}
+
+type Temperature struct {
+	Value float32
+	Unit  Unit
+}
+
+func newUnits(unitSel byte) Units {
+	units := Units{
+		Acceleration: UnitMetersPerSecondSquared,
+		AngularRate:  UnitDegreesPerSecond,
+		Angle:        UnitDegrees,
+		Temperature:  UnitCelsius,
+		Orientation:  OrientationWindows,
+	}
+
+	if unitSel&UnitSelAccMg != 0 {
+		units.Acceleration = UnitMilliG
+	}
+
+	if unitSel&UnitSelGyrRps != 0 {
+		units.AngularRate = UnitRadiansPerSecond
+	}
+
+	if unitSel&UnitSelEulRad != 0 {
+		units.Angle = UnitRadians
+	}
+
+	if unitSel&UnitSelTempF != 0 {
+		units.Temperature = UnitFahrenheit
+	}
+
+	if unitSel&UnitSelOrientationAndroid != 0 {
+		units.Orientation = OrientationAndroid
+	}
+
+	return units
+}
+
+// Returns the UNIT_SEL value for the units
+func (u Units) register() (byte, error) {
+	var unitSel byte
+
+	switch u.Acceleration {
+	case "", UnitMetersPerSecondSquared:
+	case UnitMilliG:
+		unitSel |= UnitSelAccMg
+	default:
+		return 0, fmt.Errorf("bno055: invalid acceleration unit %q", u.Acceleration)
+	}
+
+	switch u.AngularRate {
+	case "", UnitDegreesPerSecond:
+	case UnitRadiansPerSecond:
+		unitSel |= UnitSelGyrRps
+	default:
+		return 0, fmt.Errorf("bno055: invalid angular rate unit %q", u.AngularRate)
+	}
+
+	switch u.Angle {
+	case "", UnitDegrees:
+	case UnitRadians:
+		unitSel |= UnitSelEulRad
+	default:
+		return 0, fmt.Errorf("bno055: invalid angle unit %q", u.Angle)
+	}
+
+	switch u.Temperature {
+	case "", UnitCelsius:
+	case UnitFahrenheit:
+		unitSel |= UnitSelTempF
+	default:
+		return 0, fmt.Errorf("bno055: invalid temperature unit %q", u.Temperature)
+	}
+
+	switch u.Orientation {
+	case OrientationWindows:
+	case OrientationAndroid:
+		unitSel |= UnitSelOrientationAndroid
+	default:
+		return 0, fmt.Errorf("bno055: invalid orientation %d", u.Orientation)
+	}
+
+	return unitSel, nil
+}
+
+// Scale factors (LSB per unit) are taken from section 3.6.4 of the datasheet
+
+func (u Units) accelerationScale() float32 {
+	if u.Acceleration == UnitMilliG {
+		return 1
+	}
+
+	return 100
+}
+
+func (u Units) angularRateScale() float32 {
+	if u.AngularRate == UnitRadiansPerSecond {
+		return 900
+	}
+
+	return 16
+}
+
+func (u Units) angleScale() float32 {
+	if u.Angle == UnitRadians {
+		return 900
+	}
+
+	return 16
+}
+
+func (u Units) temperatureScale() float32 {
+	if u.Temperature == UnitFahrenheit {
+		return 0.5
+	}
+
+	return 1
+}
+
+// Units returns the units last set on the sensor.
+func (s *Sensor) Units() Units {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return newUnits(s.unitSel)
+}
+
+func (s *Sensor) SetUnits(units Units) error {
+	return s.SetUnitsContext(context.Background(), units)
+}
+
+func (s *Sensor) SetUnitsContext(ctx context.Context, units Units) error {
+	unitSel, err := units.register()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.inConfigMode(ctx, func() error {
+		err := s.write(ctx, bno055UnitSel, unitSel)
+		if err != nil {
+			return err
+		}
+
+		s.unitSel = unitSel
+
+		return nil
+	})
+}
@@ -0,0 +1,94 @@
+package bno055_test
+
+import (
+	"testing"
+
+	"github.com/kpeu3i/bno055"
+)
+
+func TestEulerRadians(t *testing.T) {
+	sensor, device := newTestSensor(t)
+
+	err := sensor.SetUnits(bno055.Units{Angle: bno055.UnitRadians})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	device.SetEuler(900, 1800, -450)
+
+	euler, err := sensor.Euler()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := bno055.Vector{X: 1, Y: 2, Z: -0.5, Unit: bno055.UnitRadians}
+	if *euler != want {
+		t.Fatalf("Euler = %+v, want %+v", *euler, want)
+	}
+}
+
+func TestUnitScaling(t *testing.T) {
+	sensor, device := newTestSensor(t, bno055.WithOperationMode(bno055.OperationModeAMG))
+
+	device.SetAccelerometer(981, -100, 50)
+	device.SetGyroscope(900, -1800, 0)
+	device.SetTemperature(-20)
+
+	tests := []struct {
+		units       bno055.Units
+		accel       bno055.Vector
+		gyro        bno055.Vector
+		temperature bno055.Temperature
+	}{
+		{
+			units:       bno055.Units{},
+			accel:       bno055.Vector{X: 9.81, Y: -1, Z: 0.5, Unit: bno055.UnitMetersPerSecondSquared},
+			gyro:        bno055.Vector{X: 56.25, Y: -112.5, Z: 0, Unit: bno055.UnitDegreesPerSecond},
+			temperature: bno055.Temperature{Value: -20, Unit: bno055.UnitCelsius},
+		},
+		{
+			units: bno055.Units{
+				Acceleration: bno055.UnitMilliG,
+				AngularRate:  bno055.UnitRadiansPerSecond,
+				Temperature:  bno055.UnitFahrenheit,
+			},
+			accel:       bno055.Vector{X: 981, Y: -100, Z: 50, Unit: bno055.UnitMilliG},
+			gyro:        bno055.Vector{X: 1, Y: -2, Z: 0, Unit: bno055.UnitRadiansPerSecond},
+			temperature: bno055.Temperature{Value: -40, Unit: bno055.UnitFahrenheit},
+		},
+	}
+
+	for _, test := range tests {
+		err := sensor.SetUnits(test.units)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		accel, err := sensor.Accelerometer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if *accel != test.accel {
+			t.Errorf("Accelerometer = %+v, want %+v", *accel, test.accel)
+		}
+
+		gyro, err := sensor.Gyroscope()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if *gyro != test.gyro {
+			t.Errorf("Gyroscope = %+v, want %+v", *gyro, test.gyro)
+		}
+
+		temperature, err := sensor.Temperature()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if *temperature != test.temperature {
+			t.Errorf("Temperature = %+v, want %+v", *temperature, test.temperature)
+		}
+	}
+}